@@ -3,14 +3,12 @@ package activationprofiles
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 )
 
 // apListItem represents an activation profile in the list response
@@ -30,6 +28,7 @@ func DataSourceActivationProfiles() *schema.Resource {
 		ReadContext: dataSourceActivationProfilesRead,
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.DataSourceCustomerIDSchema(),
 			"profiles": {
 				Type:        schema.TypeList,
 				Computed:    true,
@@ -54,29 +53,13 @@ func DataSourceActivationProfiles() *schema.Resource {
 }
 
 func dataSourceActivationProfilesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	req, err := http.NewRequest("GET", "https://radar.wandera.com/gate/activation-profile-service/v1/enrollment-links", nil)
+	c, err := auth.ClientFor(d, meta)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to build activation profiles list request: %v", err))
+		return diag.FromErr(err)
 	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("activation profiles list request failed: %v", err))
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return diag.FromErr(fmt.Errorf("failed to list activation profiles: %s", resp.Status))
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
+	response, err := client.Get[apListResponse](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/activation-profile-service/v1/enrollment-links"))
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to read activation profiles response: %v", err))
-	}
-
-	var response apListResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return diag.FromErr(fmt.Errorf("failed to parse activation profiles response: %v", err))
+		return client.Diagnostics(err, "failed to list activation profiles", nil)
 	}
 
 	profileList := make([]map[string]interface{}, len(response.Links))
@@ -0,0 +1,39 @@
+// Copyright 2025, Jamf Software LLC.
+package activationprofiles_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccActivationProfilesDataSource_basic(t *testing.T) {
+	s := fakejsc.New(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+resource "jsc_ap" "test" {
+  name          = "Corporate devices"
+  privateaccess = false
+}
+
+data "jsc_activation_profiles" "test" {
+  depends_on = [jsc_ap.test]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jsc_activation_profiles.test", "profiles.#", "1"),
+					resource.TestCheckResourceAttrPair("data.jsc_activation_profiles.test", "profiles.0.id", "jsc_ap.test", "id"),
+					resource.TestCheckResourceAttr("data.jsc_activation_profiles.test", "profiles.0.name", "Corporate devices"),
+				),
+			},
+		},
+	})
+}
@@ -2,156 +2,47 @@
 package activationprofiles
 
 import (
-	"fmt"
-	"io/ioutil"
+	"context"
 	"net/http"
 
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 )
 
-func getAPSupervisedManagedAppConfig(apID string) string {
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/uem-deployment-template-service/v1/activation-profiles/%s/uems/JAMF/platforms/SUPERVISED_IOS/types/MANAGED_APP_CONFIG", apID), nil)
-	if err != nil {
-		return "payload not found"
-	}
-	resp, err := auth.MakeRequest((req))
-
+// getAPPayload downloads one of the generated UEM payloads of an activation profile.
+func getAPPayload(ctx context.Context, c *auth.Client, apID string, platform string, payloadType string) string {
+	body, err := client.Send(ctx, c.MakeRequest, http.MethodGet, client.URL("https://radar.wandera.com/gate/uem-deployment-template-service/v1/activation-profiles/{id}/uems/JAMF/platforms/{platform}/types/{type}").
+		Param("id", apID).Param("platform", platform).Param("type", payloadType), nil)
 	if err != nil {
 		return "payload not found"
 	}
-	defer resp.Body.Close()
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return "payload not found"
-	}
-	body, err := ioutil.ReadAll(resp.Body)
-
 	return string(body)
 }
 
-func getAPSupervisedPlist(apID string) string {
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/uem-deployment-template-service/v1/activation-profiles/%s/uems/JAMF/platforms/SUPERVISED_IOS/types/CONFIGURATION_PROFILE", apID), nil)
-	if err != nil {
-		return "payload not found"
-	}
-	resp, err := auth.MakeRequest((req))
-
-	if err != nil {
-		return "payload not found"
-	}
-	defer resp.Body.Close()
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return "payload not found"
-	}
-	body, err := ioutil.ReadAll(resp.Body)
-
-	return string(body)
+func getAPSupervisedManagedAppConfig(ctx context.Context, c *auth.Client, apID string) string {
+	return getAPPayload(ctx, c, apID, "SUPERVISED_IOS", "MANAGED_APP_CONFIG")
 }
 
-func getAPUnSupervisedManagedAppConfig(apID string) string {
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/uem-deployment-template-service/v1/activation-profiles/%s/uems/JAMF/platforms/UNSUPERVISED_IOS/types/MANAGED_APP_CONFIG", apID), nil)
-	if err != nil {
-		return "payload not found"
-	}
-	resp, err := auth.MakeRequest((req))
-
-	if err != nil {
-		return "payload not found"
-	}
-	defer resp.Body.Close()
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return "payload not found"
-	}
-	body, err := ioutil.ReadAll(resp.Body)
-
-	return string(body)
+func getAPSupervisedPlist(ctx context.Context, c *auth.Client, apID string) string {
+	return getAPPayload(ctx, c, apID, "SUPERVISED_IOS", "CONFIGURATION_PROFILE")
 }
 
-func getAPUnSupervisedPlist(apID string) string {
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/uem-deployment-template-service/v1/activation-profiles/%s/uems/JAMF/platforms/UNSUPERVISED_IOS/types/CONFIGURATION_PROFILE", apID), nil)
-	if err != nil {
-		return "payload not found"
-	}
-	resp, err := auth.MakeRequest((req))
-
-	if err != nil {
-		return "payload not found"
-	}
-	defer resp.Body.Close()
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return "payload not found"
-	}
-	body, err := ioutil.ReadAll(resp.Body)
-
-	return string(body)
+func getAPUnSupervisedManagedAppConfig(ctx context.Context, c *auth.Client, apID string) string {
+	return getAPPayload(ctx, c, apID, "UNSUPERVISED_IOS", "MANAGED_APP_CONFIG")
 }
 
-func getAPBYODManagedAppConfig(apID string) string {
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/uem-deployment-template-service/v1/activation-profiles/%s/uems/JAMF/platforms/BYOD_IOS/types/MANAGED_APP_CONFIG", apID), nil)
-	if err != nil {
-		return "payload not found"
-	}
-	resp, err := auth.MakeRequest((req))
-
-	if err != nil {
-		return "payload not found"
-	}
-	defer resp.Body.Close()
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return "payload not found"
-	}
-	body, err := ioutil.ReadAll(resp.Body)
-
-	return string(body)
+func getAPUnSupervisedPlist(ctx context.Context, c *auth.Client, apID string) string {
+	return getAPPayload(ctx, c, apID, "UNSUPERVISED_IOS", "CONFIGURATION_PROFILE")
 }
 
-func getAPBYODPlist(apID string) string {
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/uem-deployment-template-service/v1/activation-profiles/%s/uems/JAMF/platforms/BYOD_IOS/types/CONFIGURATION_PROFILE", apID), nil)
-	if err != nil {
-		return "payload not found"
-	}
-	resp, err := auth.MakeRequest((req))
-
-	if err != nil {
-		return "payload not found"
-	}
-	defer resp.Body.Close()
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return "payload not found"
-	}
-	body, err := ioutil.ReadAll(resp.Body)
-
-	return string(body)
+func getAPBYODManagedAppConfig(ctx context.Context, c *auth.Client, apID string) string {
+	return getAPPayload(ctx, c, apID, "BYOD_IOS", "MANAGED_APP_CONFIG")
 }
 
-func getAPmacOSPlist(apID string) string {
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/uem-deployment-template-service/v1/activation-profiles/%s/uems/JAMF/platforms/SUPERVISED_MAC/types/CONFIGURATION_PROFILE", apID), nil)
-	if err != nil {
-		return "payload not found"
-	}
-	resp, err := auth.MakeRequest((req))
-
-	if err != nil {
-		return "payload not found"
-	}
-	defer resp.Body.Close()
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return "payload not found"
-	}
-	body, err := ioutil.ReadAll(resp.Body)
+func getAPBYODPlist(ctx context.Context, c *auth.Client, apID string) string {
+	return getAPPayload(ctx, c, apID, "BYOD_IOS", "CONFIGURATION_PROFILE")
+}
 
-	return string(body)
+func getAPmacOSPlist(ctx context.Context, c *auth.Client, apID string) string {
+	return getAPPayload(ctx, c, apID, "SUPERVISED_MAC", "CONFIGURATION_PROFILE")
 }
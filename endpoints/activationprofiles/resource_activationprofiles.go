@@ -2,15 +2,15 @@
 package activationprofiles
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"strings"
+	"time"
 
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -504,16 +504,23 @@ func validateIdP(v interface{}, k string) (ws []string, errors []error) {
 // Define the schema for the activation resource - only resource
 func ResourceActivationProfile() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceAPCreate,
-		Read:   resourceAPRead,
-		Update: resourceAPUpdate,
-		Delete: resourceAPDelete,
+		CreateContext: resourceAPCreate,
+		ReadContext:   resourceAPRead,
+		UpdateContext: resourceAPUpdate,
+		DeleteContext: resourceAPDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: auth.ImportCustomerState,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		// Define the attributes of the okta resource
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.CustomerIDSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -592,70 +599,48 @@ func ResourceActivationProfile() *schema.Resource {
 	}
 }
 
+// apAttributePaths points JSC validation errors for the enrollment link request at the
+// attributes they were built from.
+var apAttributePaths = client.AttributePaths{
+	"name":                                 "name",
+	"idp.connectionId":                     "oktaconnectionid",
+	"capabilities.privateAccess.enabled":   "privateaccess",
+	"capabilities.dataPolicy.enabled":      "datapolicy",
+	"capabilities.networkSecurity.enabled": "threatdefence",
+}
+
 // Define the create function for the UEMC resource
-func resourceAPCreate(d *schema.ResourceData, m interface{}) error {
-	var payload []byte
-	var err error // Declare `err` outside of the `if` block
+func resourceAPCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var payload interface{}
 	lowercaseValue := strings.ToLower(d.Get("idptype").(string))
 	if lowercaseValue == "okta" {
-		data := makepayloadstruct(d.Get("name").(string), d.Get("oktaconnectionid").(string), d.Get("privateaccess").(bool), d.Get("threatdefence").(bool), d.Get("datapolicy").(bool))
-		payload, err = json.Marshal(data)
-		if err != nil {
-			return fmt.Errorf("an error occurred: %s", "marshaling json")
-		}
+		payload = makepayloadstruct(d.Get("name").(string), d.Get("oktaconnectionid").(string), d.Get("privateaccess").(bool), d.Get("threatdefence").(bool), d.Get("datapolicy").(bool))
 	} else if lowercaseValue == "networkrelay" {
-		data := makepayloadstructNR(d.Get("name").(string))
-		payload, err = json.Marshal(data)
-		if err != nil {
-			return fmt.Errorf("an error occurred: %s", "marshaling json")
-		}
+		payload = makepayloadstructNR(d.Get("name").(string))
 	} else { //none for idp
-		data := makepayloadstructnoidp(d.Get("name").(string), d.Get("threatdefence").(bool), d.Get("datapolicy").(bool))
-		payload, err = json.Marshal(data)
-		if err != nil {
-			return fmt.Errorf("an error occurred: %s", "marshaling json")
-		}
-	}
-
-	req, err := http.NewRequest("POST", "https://radar.wandera.com/gate/activation-profile-service/v2/enrollment-links?appBrand=JAMF_TRUST", bytes.NewBuffer(payload))
-	if err != nil {
-		return fmt.Errorf("an error occurred: %s", "additional information2")
-	}
-	resp, err := auth.MakeRequest((req))
-
-	if err != nil {
-		return fmt.Errorf("an error occurred: %s", "additional information3")
-	}
-	defer resp.Body.Close()
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != 201 {
-		return fmt.Errorf("failed to create activation profile  : %s", resp.Status+" "+string(payload))
-	}
-
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("an error occurred: %s", "additional information4")
+		payload = makepayloadstructnoidp(d.Get("name").(string), d.Get("threatdefence").(bool), d.Get("datapolicy").(bool))
 	}
 
-	// Parse the response JSON
-	var response struct {
+	response, err := client.Post[struct {
 		Code string `json:"code"`
-	}
-	err = json.Unmarshal(body, &response)
+	}](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/activation-profile-service/v2/enrollment-links").Query("appBrand", "JAMF_TRUST"), payload)
 	if err != nil {
-		return err
+		return client.Diagnostics(err, "failed to create activation profile", apAttributePaths)
 	}
 
 	// Set the resource ID
 	d.SetId(response.Code)
-	d.Set("supervisedappconfig", getAPSupervisedManagedAppConfig(response.Code))
-	d.Set("supervisedplist", getAPSupervisedPlist(response.Code))
-	d.Set("unsupervisedappconfig", getAPUnSupervisedManagedAppConfig(response.Code))
-	d.Set("unsupervisedplist", getAPUnSupervisedPlist(response.Code))
-	d.Set("byodappconfig", getAPBYODManagedAppConfig(response.Code))
-	d.Set("byodplist", getAPBYODPlist(response.Code))
-	d.Set("macosplist", getAPmacOSPlist(response.Code))
+	d.Set("supervisedappconfig", getAPSupervisedManagedAppConfig(ctx, c, response.Code))
+	d.Set("supervisedplist", getAPSupervisedPlist(ctx, c, response.Code))
+	d.Set("unsupervisedappconfig", getAPUnSupervisedManagedAppConfig(ctx, c, response.Code))
+	d.Set("unsupervisedplist", getAPUnSupervisedPlist(ctx, c, response.Code))
+	d.Set("byodappconfig", getAPBYODManagedAppConfig(ctx, c, response.Code))
+	d.Set("byodplist", getAPBYODPlist(ctx, c, response.Code))
+	d.Set("macosplist", getAPmacOSPlist(ctx, c, response.Code))
 
 	return nil
 
@@ -689,39 +674,19 @@ type apReadResponse struct {
 }
 
 // Define the read function for the AP resource
-func resourceAPRead(d *schema.ResourceData, m interface{}) error {
-	// Make a GET request to read the details of an existing AP
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/activation-profile-service/v1/enrollment-links/%s", d.Id()), nil)
+func resourceAPRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	resp, err := auth.MakeRequest((req))
-
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Check the response status code
-	if resp.StatusCode == http.StatusNotFound {
+	// Make a GET request to read the details of an existing AP
+	response, err := client.Get[apReadResponse](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/activation-profile-service/v1/enrollment-links/{id}").Param("id", d.Id()))
+	if client.IsNotFound(err) {
 		d.SetId("")
 		return nil
 	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to read AP info: %s", resp.Status)
-	}
-
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return err
-	}
-
-	// Parse the response JSON
-	var response apReadResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return fmt.Errorf("failed to parse AP response: %v", err)
+		return client.Diagnostics(err, "failed to read activation profile", nil)
 	}
 
 	// Set name
@@ -745,19 +710,23 @@ func resourceAPRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("datapolicy", response.Capabilities.DataPolicy.Enabled)
 
 	// Set computed plist/appconfig values
-	d.Set("supervisedappconfig", getAPSupervisedManagedAppConfig(d.Id()))
-	d.Set("supervisedplist", getAPSupervisedPlist(d.Id()))
-	d.Set("unsupervisedappconfig", getAPUnSupervisedManagedAppConfig(d.Id()))
-	d.Set("unsupervisedplist", getAPUnSupervisedPlist(d.Id()))
-	d.Set("byodappconfig", getAPBYODManagedAppConfig(d.Id()))
-	d.Set("byodplist", getAPBYODPlist(d.Id()))
-	d.Set("macosplist", getAPmacOSPlist(d.Id()))
+	d.Set("supervisedappconfig", getAPSupervisedManagedAppConfig(ctx, c, d.Id()))
+	d.Set("supervisedplist", getAPSupervisedPlist(ctx, c, d.Id()))
+	d.Set("unsupervisedappconfig", getAPUnSupervisedManagedAppConfig(ctx, c, d.Id()))
+	d.Set("unsupervisedplist", getAPUnSupervisedPlist(ctx, c, d.Id()))
+	d.Set("byodappconfig", getAPBYODManagedAppConfig(ctx, c, d.Id()))
+	d.Set("byodplist", getAPBYODPlist(ctx, c, d.Id()))
+	d.Set("macosplist", getAPmacOSPlist(ctx, c, d.Id()))
 
 	return nil
 }
 
 // resourceAPUpdate updates an activation profile (only name can be updated)
-func resourceAPUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceAPUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	if !d.HasChange("name") {
 		return nil
 	}
@@ -769,49 +738,24 @@ func resourceAPUpdate(d *schema.ResourceData, m interface{}) error {
 		"groupId": "DEFAULT",
 	}
 
-	payload, err := json.Marshal(updatePayload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal update payload: %v", err)
-	}
-
-	req, err := http.NewRequest("PUT", fmt.Sprintf("https://radar.wandera.com/gate/activation-profile-service/v1/enrollment-links/%s?appBrand=JAMF_TRUST", d.Id()), bytes.NewBuffer(payload))
-	if err != nil {
-		return fmt.Errorf("failed to create update request: %v", err)
-	}
-
-	resp, err := auth.MakeRequest(req)
+	err = client.Put(ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/activation-profile-service/v1/enrollment-links/{id}").Param("id", d.Id()).Query("appBrand", "JAMF_TRUST"), updatePayload)
 	if err != nil {
-		return fmt.Errorf("failed to execute update request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("failed to update activation profile: %s - %s", resp.Status, string(body))
+		return client.Diagnostics(err, "failed to update activation profile", apAttributePaths)
 	}
 
-	return resourceAPRead(d, m)
+	return resourceAPRead(ctx, d, m)
 }
 
 // need to apply this function
-func resourceAPDelete(d *schema.ResourceData, m interface{}) error {
-	// Make a DELETE request to delete an existing AP
-
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("https://radar.wandera.com/gate/activation-profile-service/v1/enrollment-links/%s", d.Id()), nil)
+func resourceAPDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-
-	// Send the request
-	resp, err := auth.MakeRequest((req))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != 204 {
-		return fmt.Errorf("failed to delete AP: %v %v %v", resp.Status, resp, req)
+	// Make a DELETE request to delete an existing AP
+	err = client.Delete(ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/activation-profile-service/v1/enrollment-links/{id}").Param("id", d.Id()))
+	if err != nil && !client.IsNotFound(err) {
+		return client.Diagnostics(err, "failed to delete activation profile", nil)
 	}
 
 	// Clear the resource ID
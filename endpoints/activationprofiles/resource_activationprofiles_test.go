@@ -0,0 +1,56 @@
+// Copyright 2025, Jamf Software LLC.
+package activationprofiles_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccActivationProfile_basic(t *testing.T) {
+	s := fakejsc.New(t)
+	config := func(name string) string {
+		// Private access needs an IdP, so it is off for a profile without one
+		return acctest.ProviderConfig(s) + `
+resource "jsc_ap" "test" {
+  name          = "` + name + `"
+  idptype       = "None"
+  privateaccess = false
+}
+`
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		CheckDestroy:             acctest.CheckDestroy(s, fakejsc.EnrollmentLinks, "jsc_ap"),
+		Steps: []resource.TestStep{
+			{
+				Config: config("Corporate devices"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("jsc_ap.test", "id"),
+					resource.TestCheckResourceAttr("jsc_ap.test", "name", "Corporate devices"),
+					resource.TestCheckResourceAttrSet("jsc_ap.test", "supervisedplist"),
+					resource.TestCheckResourceAttrSet("jsc_ap.test", "macosplist"),
+				),
+			},
+			{
+				Config: config("Corporate iOS devices"),
+				Check:  resource.TestCheckResourceAttr("jsc_ap.test", "name", "Corporate iOS devices"),
+			},
+			{
+				ResourceName:      "jsc_ap.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:             config("Corporate iOS devices"),
+				Check:              acctest.Disappears(s, fakejsc.EnrollmentLinks, "jsc_ap.test"),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
@@ -2,14 +2,15 @@
 package admin
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 )
 
 type adminProfile struct {
@@ -66,6 +67,17 @@ func toStringSlice(in []interface{}) []string {
 	return out
 }
 
+// adminAttributePaths points JSC validation errors for the admin request at the attributes they
+// were built from.
+var adminAttributePaths = client.AttributePaths{
+	"profile.name":                                          "name",
+	"authentication.username":                               "username",
+	"authentication.sso.enabled":                            "sso_enabled",
+	"authorization.roles":                                   "roles",
+	"authorization.permissions":                             "permissions",
+	"notificationSettings.subscribedNotificationCategories": "notification_categories",
+}
+
 func buildAdminRequest(d *schema.ResourceData) adminRequest {
 	return adminRequest{
 		Profile: adminProfile{
@@ -108,15 +120,16 @@ func suppressPermissionDiffForSuperAdmin(k, oldValue, newValue string, d *schema
 // ResourceAdmin returns the schema.Resource for jsc_admin.
 func ResourceAdmin() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceAdminCreate,
-		Read:   resourceAdminRead,
-		Update: resourceAdminUpdate,
-		Delete: resourceAdminDelete,
+		CreateContext: resourceAdminCreate,
+		ReadContext:   resourceAdminRead,
+		UpdateContext: resourceAdminUpdate,
+		DeleteContext: resourceAdminDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: auth.ImportCustomerState,
 		},
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.CustomerIDSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -157,68 +170,40 @@ func ResourceAdmin() *schema.Resource {
 	}
 }
 
-func resourceAdminCreate(d *schema.ResourceData, m interface{}) error {
-	payload, err := json.Marshal(buildAdminRequest(d))
-	if err != nil {
-		return fmt.Errorf("failed to marshal jsc_admin payload: %v", err)
-	}
+// The admin service takes the customer in the path only
+var (
+	adminsEndpoint = client.URL("https://radar.wandera.com/gate/admin-service/v4/customers/{customerid}/admins").Customer(client.CustomerPath)
+	adminEndpoint  = client.URL("https://radar.wandera.com/gate/admin-service/v4/customers/{customerid}/admins/{id}").Customer(client.CustomerPath)
+)
 
-	req, err := http.NewRequest("POST", "https://radar.wandera.com/gate/admin-service/v4/customers/{customerid}/admins", bytes.NewBuffer(payload))
+// listAdmins returns the first page of admins for the customer, which is where new admins appear.
+func listAdmins(ctx context.Context, c *auth.Client) (*adminListResponse, error) {
+	listResponse, err := client.Get[adminListResponse](ctx, c.MakeRequest, adminsEndpoint.Query("page", "0").Query("pageSize", "100"))
 	if err != nil {
-		return fmt.Errorf("failed to build jsc_admin create request: %v", err)
+		return nil, fmt.Errorf("failed to list admins: %w", err)
 	}
+	return listResponse, nil
+}
 
-	resp, err := auth.MakeRequest(req)
+func resourceAdminCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("jsc_admin create request failed: %v", err)
+		return diag.FromErr(err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("failed to create jsc_admin: %s", resp.Status)
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
+	body, err := client.Send(ctx, c.MakeRequest, http.MethodPost, adminsEndpoint, buildAdminRequest(d))
 	if err != nil {
-		return fmt.Errorf("failed to read jsc_admin create response: %v", err)
+		return client.Diagnostics(err, "failed to create jsc_admin", adminAttributePaths)
 	}
 
 	// The API returns an empty body on successful creation.
 	// We need to list admins with pagination to find the newly created admin and get its ID.
-	if len(body) == 0 || string(body) == "" {
+	if len(body) == 0 {
 		username := d.Get("username").(string)
 
-		// List admins with pagination to find the newly created admin
-		// Note: Don't add query params here - auth.MakeRequest adds customerId and will break the URL
-		listReq, err := http.NewRequest("GET", "https://radar.wandera.com/gate/admin-service/v4/customers/{customerid}/admins", nil)
-		if err != nil {
-			return fmt.Errorf("failed to build admin list request: %v", err)
-		}
-
-		// Manually construct query string with pagination params
-		q := listReq.URL.Query()
-		q.Add("page", "0")
-		q.Add("pageSize", "100")
-		listReq.URL.RawQuery = q.Encode()
-
-		listResp, err := auth.MakeRequest(listReq)
+		listResponse, err := listAdmins(ctx, c)
 		if err != nil {
-			return fmt.Errorf("admin list request failed: %v", err)
-		}
-		defer listResp.Body.Close()
-
-		if listResp.StatusCode != http.StatusOK {
-			return fmt.Errorf("failed to list admins: %s", listResp.Status)
-		}
-
-		listBody, err := ioutil.ReadAll(listResp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read admin list response: %v", err)
-		}
-
-		var listResponse adminListResponse
-		if err := json.Unmarshal(listBody, &listResponse); err != nil {
-			return fmt.Errorf("failed to parse admin list response: %v", err)
+			return diag.FromErr(err)
 		}
 
 		// Find our admin by username
@@ -231,7 +216,7 @@ func resourceAdminCreate(d *schema.ResourceData, m interface{}) error {
 		}
 
 		if adminID == "" {
-			return fmt.Errorf("admin was created but could not be found in the list (username: %s)", username)
+			return diag.FromErr(fmt.Errorf("admin was created but could not be found in the list (username: %s)", username))
 		}
 
 		// Set the real admin ID
@@ -244,48 +229,26 @@ func resourceAdminCreate(d *schema.ResourceData, m interface{}) error {
 		ID string `json:"id"`
 	}
 	if err := json.Unmarshal(body, &response); err != nil {
-		return fmt.Errorf("failed to parse jsc_admin create response: %v (body was: %s)", err, string(body))
+		return diag.FromErr(fmt.Errorf("failed to parse jsc_admin create response: %v (body was: %s)", err, string(body)))
 	}
 
 	if response.ID == "" {
-		return fmt.Errorf("jsc_admin was created but API returned an empty ID")
+		return diag.FromErr(fmt.Errorf("jsc_admin was created but API returned an empty ID"))
 	}
 
 	d.SetId(response.ID)
 	return nil
 }
 
-func resourceAdminRead(d *schema.ResourceData, m interface{}) error {
-	// List admins with pagination to find ours by ID
-	req, err := http.NewRequest("GET", "https://radar.wandera.com/gate/admin-service/v4/customers/{customerid}/admins", nil)
-	if err != nil {
-		return fmt.Errorf("failed to build admin list request: %v", err)
-	}
-
-	// Add pagination query params properly
-	q := req.URL.Query()
-	q.Add("page", "0")
-	q.Add("pageSize", "100")
-	req.URL.RawQuery = q.Encode()
-
-	resp, err := auth.MakeRequest(req)
+func resourceAdminRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("admin list request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to list admins: %s", resp.Status)
+		return diag.FromErr(err)
 	}
-
-	body, err := ioutil.ReadAll(resp.Body)
+	// List admins with pagination to find ours by ID
+	listResponse, err := listAdmins(ctx, c)
 	if err != nil {
-		return fmt.Errorf("failed to read admin list response: %v", err)
-	}
-
-	var listResponse adminListResponse
-	if err := json.Unmarshal(body, &listResponse); err != nil {
-		return fmt.Errorf("failed to parse admin list response: %v", err)
+		return diag.FromErr(err)
 	}
 
 	// Find our admin by ID
@@ -309,51 +272,33 @@ func resourceAdminRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceAdminUpdate(d *schema.ResourceData, m interface{}) error {
-	adminID := d.Id()
-
-	payload, err := json.Marshal(buildAdminRequest(d))
-	if err != nil {
-		return fmt.Errorf("failed to marshal jsc_admin update payload: %v", err)
-	}
-
-	req, err := http.NewRequest("PUT", fmt.Sprintf("https://radar.wandera.com/gate/admin-service/v4/customers/{customerid}/admins/%s", adminID), bytes.NewBuffer(payload))
+func resourceAdminUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("failed to build jsc_admin update request: %v", err)
+		return diag.FromErr(err)
 	}
+	adminID := d.Id()
 
-	resp, err := auth.MakeRequest(req)
+	err = client.Put(ctx, c.MakeRequest, adminEndpoint.Param("id", adminID), buildAdminRequest(d))
 	if err != nil {
-		return fmt.Errorf("jsc_admin update request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("failed to update jsc_admin: %s (response: %s)", resp.Status, string(body))
+		return client.Diagnostics(err, "failed to update jsc_admin", adminAttributePaths)
 	}
 
 	// Read back the updated state
-	return resourceAdminRead(d, m)
+	return resourceAdminRead(ctx, d, m)
 }
 
-func resourceAdminDelete(d *schema.ResourceData, m interface{}) error {
-	// Use the admin ID directly (retrieved during create/read)
-	adminID := d.Id()
-
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("https://radar.wandera.com/gate/admin-service/v4/customers/{customerid}/admins/%s", adminID), nil)
+func resourceAdminDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("failed to build jsc_admin delete request: %v", err)
+		return diag.FromErr(err)
 	}
+	// Use the admin ID directly (retrieved during create/read)
+	adminID := d.Id()
 
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return fmt.Errorf("jsc_admin delete request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
-		return fmt.Errorf("failed to delete jsc_admin: %s", resp.Status)
+	err = client.Delete(ctx, c.MakeRequest, adminEndpoint.Param("id", adminID))
+	if err != nil && !client.IsNotFound(err) {
+		return client.Diagnostics(err, "failed to delete jsc_admin", nil)
 	}
 
 	d.SetId("")
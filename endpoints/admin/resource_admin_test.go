@@ -0,0 +1,102 @@
+// Copyright 2025, Jamf Software LLC.
+package admin_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccAdmin_basic(t *testing.T) {
+	s := fakejsc.New(t)
+	config := func(name string) string {
+		return acctest.ProviderConfig(s) + `
+resource "jsc_admin" "test" {
+  name                    = "` + name + `"
+  username                = "jane.doe@example.com"
+  permissions             = ["DEVICES", "ACCESS"]
+  notification_categories = ["SECURITY"]
+}
+`
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		CheckDestroy:             acctest.CheckDestroy(s, fakejsc.Admins, "jsc_admin"),
+		Steps: []resource.TestStep{
+			{
+				Config: config("Jane Doe"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("jsc_admin.test", "id"),
+					resource.TestCheckResourceAttr("jsc_admin.test", "name", "Jane Doe"),
+					resource.TestCheckResourceAttr("jsc_admin.test", "permissions.#", "2"),
+					resource.TestCheckTypeSetElemAttr("jsc_admin.test", "notification_categories.*", "SECURITY"),
+				),
+			},
+			{
+				Config: config("Jane Smith"),
+				Check:  resource.TestCheckResourceAttr("jsc_admin.test", "name", "Jane Smith"),
+			},
+			{
+				ResourceName:      "jsc_admin.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:             config("Jane Smith"),
+				Check:              acctest.Disappears(s, fakejsc.Admins, "jsc_admin.test"),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccAdmin_childCustomerImport(t *testing.T) {
+	s := fakejsc.New(t)
+	s.SetCustomers(
+		fakejsc.Customer{ID: "parent", Name: "Parent", Leaf: false},
+		fakejsc.Customer{ID: "child-1", Name: "Child 1", Leaf: true, ParentID: "parent"},
+		fakejsc.Customer{ID: "child-2", Name: "Child 2", Leaf: true, ParentID: "parent"},
+	)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		CheckDestroy:             acctest.CheckDestroy(s, fakejsc.Admins, "jsc_admin"),
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+resource "jsc_admin" "test" {
+  customer_id = "child-2"
+  name        = "Jane Doe"
+  username    = "jane.doe@example.com"
+  permissions = ["DEVICES"]
+}
+`,
+			},
+			{
+				// <customer_id>/<id> imports from the child customer, not the provider default
+				ResourceName: "jsc_admin.test",
+				ImportState:  true,
+				ImportStateIdFunc: func(state *terraform.State) (string, error) {
+					return "child-2/" + state.RootModule().Resources["jsc_admin.test"].Primary.ID, nil
+				},
+				ImportStateVerify: true,
+				ImportStateCheck: func([]*terraform.InstanceState) error {
+					if slices.ContainsFunc(s.Requests(), func(r string) bool { return strings.Contains(r, "/customers/child-1/") }) {
+						return fmt.Errorf("import read from the default customer: %q", s.Requests())
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
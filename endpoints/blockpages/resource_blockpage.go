@@ -2,30 +2,52 @@
 package blockpages
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"slices"
+	"strings"
 	"sync"
 
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // Create a global mutex need to lock patch requests
 var mu sync.Mutex
 
+// blockPageTypes are the block page types the block service keeps, each under its own key of
+// the customer's block page document.
+var blockPageTypes = []string{"block", "secureBlock", "cap", "deviceRisk", "deviceManagement"}
+
+// blockPage is one block page type of the block service document.
+type blockPage struct {
+	Description        string `json:"description"`
+	Enabled            bool   `json:"enabled"`
+	Logo               string `json:"logo"`
+	ShowClassification bool   `json:"showClassification"`
+	ShowRequestURL     bool   `json:"showRequestUrl"`
+	Title              string `json:"title"`
+}
+
 // Define the schema for the blockpage resource - only datablock rn
 func ResourceBlockPage() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceBlockPageCreate,
-		Read:   resourceBlockPageRead,
-		Update: resourceBlockPageUpdate,
-		Delete: resourceBlockPageCDelete,
+		CreateContext: resourceBlockPageCreate,
+		ReadContext:   resourceBlockPageRead,
+		UpdateContext: resourceBlockPageUpdate,
+		DeleteContext: resourceBlockPageCDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: auth.ImportCustomerState,
+		},
 
 		// Define the attributes of the okta resource
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.CustomerIDSchema(),
 			"description": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -39,17 +61,12 @@ func ResourceBlockPage() *schema.Resource {
 				Description: "Title of text.",
 			},
 			"type": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "block",
-				Description: "Type of block page. 'cap' for Data Cap, 'secureBlock' for Security Block, 'deviceRisk' for Device Risk Block, 'deviceManagement' for lack of MDM block",
-				ValidateFunc: func(val any, key string) (warns []string, errs []error) {
-
-					if val != "block" && val != "secureBlock" && val != "cap" && val != "deviceRisk" && val != "deviceManagement" {
-						errs = append(errs, fmt.Errorf("%q must be either block, secureBlock, cap, deviceRisk, or deviceManagement: got: %d", key, val))
-					}
-					return
-				},
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "block",
+				Description:  "Type of block page. 'cap' for Data Cap, 'secureBlock' for Security Block, 'deviceRisk' for Device Risk Block, 'deviceManagement' for lack of MDM block",
+				ValidateFunc: validation.StringInSlice(blockPageTypes, false),
 			},
 			"show_classification": {
 				Type:     schema.TypeBool,
@@ -72,9 +89,11 @@ func ResourceBlockPage() *schema.Resource {
 	}
 }
 
-// Define the create function for the UEMC resource
-func resourceBlockPageCreate(d *schema.ResourceData, m interface{}) error {
+// blockPagesEndpoint holds every block page of the customer, which the block service takes in the path.
+var blockPagesEndpoint = client.URL("https://radar.wandera.com/gate/block-service/blocks/v1/customers/{customerid}").Customer(client.CustomerPath)
 
+// patchBlockPage enables the block page of the configured type with the configured settings.
+func patchBlockPage(ctx context.Context, c *auth.Client, d *schema.ResourceData) error {
 	vm := map[string]interface{}{
 		d.Get("type").(string): map[string]interface{}{
 			"description":        d.Get("description").(string),
@@ -91,70 +110,96 @@ func resourceBlockPageCreate(d *schema.ResourceData, m interface{}) error {
 		"privateRelayDomainsBlock":     true,
 	}
 
-	payload, err := json.Marshal(vm)
-	if err != nil {
-		return fmt.Errorf("an error occurred: %s", "additional information1")
-	}
-	req, err := http.NewRequest("PATCH", fmt.Sprintf("https://radar.wandera.com/gate/block-service/blocks/v1/customers/{customerid}"), bytes.NewBuffer(payload))
-	if err != nil {
-		return fmt.Errorf("an error occurred: %s", "additional information2")
-	}
 	// Lock the mutex to ensure only one patch can run this function at a time
 	mu.Lock()
 	defer mu.Unlock()
-	resp, err := auth.MakeRequest((req))
+	return client.Patch(ctx, c.MakeRequest, blockPagesEndpoint, vm)
+}
 
+// Define the create function for the block page resource
+func resourceBlockPageCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("an error occurred: %s", "additional information3")
-	}
-	defer resp.Body.Close()
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != 204 {
-		return fmt.Errorf("failed to create block page : %s", resp.Status+" "+string(payload))
+		return diag.FromErr(err)
 	}
 
-	// Set the resource ID... hint there's only 1 ID of UEMC!
-	d.SetId("1")
+	if err := patchBlockPage(ctx, c, d); err != nil {
+		return client.Diagnostics(err, "failed to create block page", nil)
+	}
 
-	// Set the resource ID
-	//d.SetId("example-vm-id")
+	// Each type is a separate block page, so the type is the ID
+	d.SetId(d.Get("type").(string))
 
-	return nil
+	return resourceBlockPageRead(ctx, d, m)
 }
 
 // Define the read function for the Blockpage resource
-func resourceBlockPageRead(d *schema.ResourceData, m interface{}) error {
-	// Make a GET request to read the details of an existing Okta IDP
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/block-service/blocks/v1/customers/{customerid}"), nil)
+func resourceBlockPageRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	resp, err := auth.MakeRequest((req))
 
-	//resp, err := http.Get(fmt.Sprintf("https://radar.wandera.com/gate/identity-service/v1/connections?customerId=993ae0ee-4bd8-4325-bc5d-1db0ea45b4f6&type=OKTA"))
+	// Block pages created before the ID was the type all had the ID "1"
+	if d.Id() == "1" {
+		d.SetId(d.Get("type").(string))
+	}
+	pageType := d.Id()
+	if !slices.Contains(blockPageTypes, pageType) {
+		return diag.FromErr(fmt.Errorf("unknown block page type %q, expected one of %s", pageType, strings.Join(blockPageTypes, ", ")))
+	}
+
+	// Make a GET request to read the details of the block pages. The document also holds
+	// customer wide flags, so only the entry of our type is parsed.
+	pages, err := client.Get[map[string]json.RawMessage](ctx, c.MakeRequest, blockPagesEndpoint)
 	if err != nil {
-		return err
+		return client.Diagnostics(err, "failed to read block page", nil)
 	}
-	defer resp.Body.Close()
 
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to read BlockPage info: %s", resp.Status)
+	var page blockPage
+	if raw, ok := (*pages)[pageType]; ok {
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to parse %s block page: %v", pageType, err))
+		}
 	}
 
+	// Deleting a block page disables it, so a disabled page no longer exists
+	if !page.Enabled {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("type", pageType)
+	d.Set("title", page.Title)
+	d.Set("description", page.Description)
+	d.Set("logo", page.Logo)
+	d.Set("show_classification", page.ShowClassification)
+	d.Set("show_requesturl", page.ShowRequestURL)
+
 	return nil
 }
 
-// Define the update function for the UEMC - needs to be replace completely
-func resourceBlockPageUpdate(d *schema.ResourceData, m interface{}) error {
-	d.Set("requires_replace", true)
-	resourceBlockPageCreate(d, m)
-	return nil
+// Define the update function for the block page, which overwrites the settings of its type
+func resourceBlockPageUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := patchBlockPage(ctx, c, d); err != nil {
+		return client.Diagnostics(err, "failed to update block page", nil)
+	}
+
+	return resourceBlockPageRead(ctx, d, m)
 }
 
-// Define the delete function for the block page - which doesn't really exist do we just reset back to default
-func resourceBlockPageCDelete(d *schema.ResourceData, m interface{}) error {
+// Define the delete function for the block page - which doesn't really exist do we just reset back to default.
+// The customer wide flags are left alone, as other block page types may still rely on them.
+func resourceBlockPageCDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	vm := map[string]interface{}{
 		d.Get("type").(string): map[string]interface{}{
@@ -168,31 +213,14 @@ func resourceBlockPageCDelete(d *schema.ResourceData, m interface{}) error {
 			"templateId":         "default",
 			"title":              "Site Blocked",
 		},
-		"jamfCustomizableBlockSupport": false,
-		"privateRelayDomainsBlock":     false,
 	}
 
-	payload, err := json.Marshal(vm)
-	if err != nil {
-		return err
-	}
-	req, err := http.NewRequest("PATCH", fmt.Sprintf("https://radar.wandera.com/gate/block-service/blocks/v1/customers/{customerid}"), bytes.NewBuffer(payload))
-	if err != nil {
-		return err
-	}
 	//lock to ensure only one patch can occur at one time
 	mu.Lock()
 	defer mu.Unlock()
-	resp, err := auth.MakeRequest((req))
-
+	err = client.Patch(ctx, c.MakeRequest, blockPagesEndpoint, vm)
 	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != 204 {
-		return fmt.Errorf("failed to reset block page : %s", resp.Status+" "+string(payload))
+		return client.Diagnostics(err, "failed to reset block page", nil)
 	}
 
 	// Clear the resource ID
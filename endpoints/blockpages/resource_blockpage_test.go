@@ -0,0 +1,136 @@
+// Copyright 2025, Jamf Software LLC.
+package blockpages_test
+
+import (
+	"fmt"
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBlockPage_basic(t *testing.T) {
+	s := fakejsc.New(t)
+	config := func(title string) string {
+		return acctest.ProviderConfig(s) + `
+resource "jsc_blockpage" "test" {
+  type                = "secureBlock"
+  title               = "` + title + `"
+  description         = "Blocked by policy."
+  show_classification = false
+}
+
+resource "jsc_blockpage" "cap" {
+  type  = "cap"
+  title = "Data cap reached"
+}
+`
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		// Deleting a block page disables it again
+		CheckDestroy: func(*terraform.State) error {
+			for _, pageType := range []string{"secureBlock", "cap"} {
+				if s.BlockPage(pageType)["enabled"] != false {
+					return fmt.Errorf("%s block page is still enabled", pageType)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config("Blocked"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jsc_blockpage.test", "id", "secureBlock"),
+					resource.TestCheckResourceAttr("jsc_blockpage.test", "type", "secureBlock"),
+					resource.TestCheckResourceAttr("jsc_blockpage.test", "title", "Blocked"),
+					resource.TestCheckResourceAttr("jsc_blockpage.test", "show_classification", "false"),
+					resource.TestCheckResourceAttr("jsc_blockpage.cap", "id", "cap"),
+					resource.TestCheckResourceAttr("jsc_blockpage.cap", "title", "Data cap reached"),
+				),
+			},
+			{
+				Config: config("Blocked by IT"),
+				Check:  resource.TestCheckResourceAttr("jsc_blockpage.test", "title", "Blocked by IT"),
+			},
+			{
+				ResourceName:      "jsc_blockpage.test",
+				ImportState:       true,
+				ImportStateId:     "secureBlock",
+				ImportStateVerify: true,
+			},
+			{
+				// A title changed in the portal shows up as drift
+				PreConfig:          func() { s.SetBlockPage("secureBlock", "title", "Edited in the portal") },
+				Config:             config("Blocked by IT"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config("Blocked by IT"),
+				Check:  resource.TestCheckResourceAttr("jsc_blockpage.test", "title", "Blocked by IT"),
+			},
+		},
+	})
+}
+
+func TestAccBlockPage_destroyOneType(t *testing.T) {
+	s := fakejsc.New(t)
+	capPage := `
+resource "jsc_blockpage" "cap" {
+  type  = "cap"
+  title = "Data cap reached"
+}
+`
+	checkFlags := func(*terraform.State) error {
+		for _, flag := range []string{"jamfCustomizableBlockSupport", "privateRelayDomainsBlock"} {
+			if got := s.BlockPageFlag(flag); got != true {
+				return fmt.Errorf("%s is %v, want true", flag, got)
+			}
+		}
+		return nil
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + capPage + `
+resource "jsc_blockpage" "test" {
+  type  = "secureBlock"
+  title = "Blocked"
+}
+`,
+				Check: checkFlags,
+			},
+			{
+				// Destroying secureBlock leaves cap and the customer wide flags as they were
+				Config: acctest.ProviderConfig(s) + capPage,
+				Check: resource.ComposeTestCheckFunc(
+					checkFlags,
+					resource.TestCheckResourceAttr("jsc_blockpage.cap", "id", "cap"),
+					resource.TestCheckResourceAttr("jsc_blockpage.cap", "title", "Data cap reached"),
+					func(*terraform.State) error {
+						if s.BlockPage("secureBlock")["enabled"] != false {
+							return fmt.Errorf("secureBlock block page is still enabled")
+						}
+						if page := s.BlockPage("cap"); page["enabled"] != true || page["title"] != "Data cap reached" {
+							return fmt.Errorf("cap block page changed: %v", page)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:   acctest.ProviderConfig(s) + capPage,
+				PlanOnly: true,
+			},
+		},
+	})
+}
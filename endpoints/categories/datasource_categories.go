@@ -4,14 +4,11 @@ package categories
 import (
 	//"bytes"
 	//"encoding/json"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"net/http"
 	"strings"
 
 	"context"
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -29,6 +26,7 @@ func DataSourceCategories() *schema.Resource {
 		ReadContext: dataSourceCategoriesRead,
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.DataSourceCustomerIDSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -50,41 +48,20 @@ func DataSourceCategories() *schema.Resource {
 
 // Define the read function for routes
 func dataSourceCategoriesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	//routeName := d.Get("name").(string)
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/content-block-service/v1/customers/{customerid}/categories"), nil)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("error converting making http request body"))
-	}
-	resp, err := auth.MakeRequest((req))
-
+	c, err := auth.ClientFor(d, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	defer resp.Body.Close()
-
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return diag.FromErr(fmt.Errorf("failed to read category info: %s", resp.Status))
-	}
-
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("error making parsing body response"))
-	}
-
-	// Parse the response JSON
+	//routeName := d.Get("name").(string)
 
-	var response []Categories
-	err = json.Unmarshal(body, &response)
+	response, err := client.Get[[]Categories](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/content-block-service/v1/customers/{customerid}/categories").Customer(client.CustomerPath))
 	if err != nil {
-		return diag.FromErr(err)
+		return client.Diagnostics(err, "failed to read category info", nil)
 	}
 
 	// Find id from the first instance where name contains "the provided name"
 
-	for _, category := range response {
+	for _, category := range *response {
 		if strings.EqualFold(category.DisplayName, d.Get("displayname").(string)) {
 			d.Set("name", category.Name)
 			d.SetId(category.ID) //need to set something for resource to exist
@@ -0,0 +1,123 @@
+// Copyright 2025, Jamf Software LLC.
+package currentsession
+
+import (
+	"context"
+	"fmt"
+
+	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// meResponse is the part of /auth/v1/me the data source exposes.
+type meResponse struct {
+	Admin struct {
+		ID          string   `json:"id"`
+		Username    string   `json:"username"`
+		Roles       []string `json:"roles"`
+		Permissions []string `json:"permissions"`
+		EntityType  string   `json:"entityType"`
+		EntityID    string   `json:"entityId"`
+	} `json:"admin"`
+}
+
+// DataSourceCurrentSession returns the schema.Resource for jsc_current_session, which describes
+// the admin and customer the provider is logged in as.
+func DataSourceCurrentSession() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceCurrentSessionRead,
+
+		Schema: map[string]*schema.Schema{
+			"customer_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The customer requests are sent for. Defaults to the provider customer; set it to check a customer is visible to the authenticated admin.",
+			},
+			"admin_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the authenticated admin. Empty when only PAG credentials are configured.",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The username of the authenticated admin.",
+			},
+			"roles": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The roles of the authenticated admin.",
+			},
+			"permissions": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The permissions of the authenticated admin.",
+			},
+			"entity_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Whether the admin belongs to a single customer (CUSTOMER) or a parent of several (PARENT).",
+			},
+			"entity_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the customer or parent the admin belongs to.",
+			},
+			"domain": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The JSC domain the provider is connected to.",
+			},
+			"auth_method": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "How the provider logged in: local for a JSC username and password, jamf_id when the login went through Jamf ID, or pag when only PAG application credentials are configured.",
+			},
+		},
+	}
+}
+
+func dataSourceCurrentSessionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	method := c.AuthMethod()
+	d.Set("auth_method", string(method))
+	d.Set("domain", c.Domain())
+
+	// A PAG application is not an admin and has no customer
+	if method == auth.AuthMethodPAG {
+		d.SetId(string(method))
+		return nil
+	}
+
+	me, err := client.Get[meResponse](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/auth/v1/me").Customer(client.NoCustomer))
+	if err != nil {
+		return client.Diagnostics(err, "failed to read the current session", nil)
+	}
+	if me.Admin.ID == "" && me.Admin.Username == "" {
+		return diag.FromErr(fmt.Errorf("failed to read the current session: /auth/v1/me returned no admin"))
+	}
+
+	d.Set("customer_id", c.CustomerID())
+	d.Set("admin_id", me.Admin.ID)
+	d.Set("username", me.Admin.Username)
+	d.Set("roles", me.Admin.Roles)
+	d.Set("permissions", me.Admin.Permissions)
+	d.Set("entity_type", me.Admin.EntityType)
+	d.Set("entity_id", me.Admin.EntityID)
+
+	if me.Admin.ID != "" {
+		d.SetId(me.Admin.ID)
+	} else {
+		d.SetId(me.Admin.Username)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2025, Jamf Software LLC.
+package currentsession_test
+
+import (
+	"net/url"
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCurrentSessionDataSource_basic(t *testing.T) {
+	s := fakejsc.New(t)
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+data "jsc_current_session" "test" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jsc_current_session.test", "id", fakejsc.AdminID),
+					resource.TestCheckResourceAttr("data.jsc_current_session.test", "admin_id", fakejsc.AdminID),
+					resource.TestCheckResourceAttr("data.jsc_current_session.test", "username", fakejsc.Username),
+					resource.TestCheckResourceAttr("data.jsc_current_session.test", "roles.#", "1"),
+					resource.TestCheckResourceAttr("data.jsc_current_session.test", "roles.0", "ADMIN"),
+					resource.TestCheckResourceAttr("data.jsc_current_session.test", "permissions.#", "2"),
+					resource.TestCheckResourceAttr("data.jsc_current_session.test", "entity_type", "CUSTOMER"),
+					resource.TestCheckResourceAttr("data.jsc_current_session.test", "entity_id", fakejsc.CustomerID),
+					resource.TestCheckResourceAttr("data.jsc_current_session.test", "customer_id", fakejsc.CustomerID),
+					resource.TestCheckResourceAttr("data.jsc_current_session.test", "domain", u.Host),
+					resource.TestCheckResourceAttr("data.jsc_current_session.test", "auth_method", "local"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCurrentSessionDataSource_childCustomer(t *testing.T) {
+	s := fakejsc.New(t)
+	s.SetCustomers(
+		fakejsc.Customer{ID: "parent", Name: "Parent", Leaf: false},
+		fakejsc.Customer{ID: "child-1", Name: "Child 1", Leaf: true, ParentID: "parent"},
+		fakejsc.Customer{ID: "child-2", Name: "Child 2", Leaf: true, ParentID: "parent"},
+	)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+data "jsc_current_session" "default" {}
+
+data "jsc_current_session" "child" {
+  customer_id = "child-2"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jsc_current_session.default", "entity_type", "PARENT"),
+					resource.TestCheckResourceAttr("data.jsc_current_session.default", "entity_id", "parent"),
+					resource.TestCheckResourceAttr("data.jsc_current_session.default", "customer_id", "child-1"),
+					resource.TestCheckResourceAttr("data.jsc_current_session.child", "customer_id", "child-2"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,123 @@
+// Copyright 2025, Jamf Software LLC.
+package customers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// visibleCustomer is a customer in the visible-for-admin response.
+type visibleCustomer struct {
+	CustomerID string `json:"customerId"`
+	Name       string `json:"name"`
+	Leaf       bool   `json:"leaf"`
+	ParentID   string `json:"parentId"`
+}
+
+// DataSourceCustomers returns every customer visible to the authenticated admin, so a parent
+// (MSP) admin can drive for_each over its child customers.
+func DataSourceCustomers() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceCustomersRead,
+
+		Schema: map[string]*schema.Schema{
+			"customer_id": auth.DataSourceCustomerIDSchema(),
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list customers with exactly this name.",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only list customers whose name matches this regular expression.",
+			},
+			"customers": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The customers visible to the authenticated admin, in the order JSC returns them.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The customer ID, as used by customer_id on other resources.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the customer.",
+						},
+						"leaf": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the customer is a leaf customer. Only leaf customers can be used as customer_id.",
+						},
+						"parent_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the parent customer, empty for a top level customer.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceCustomersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	response, err := client.Get[[]visibleCustomer](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/user-service/customer/v2/customers/visible-for-admin").Customer(client.NoCustomer))
+	if err != nil {
+		return client.Diagnostics(err, "failed to list visible customers", nil)
+	}
+
+	name := d.Get("name").(string)
+	var nameRegex *regexp.Regexp
+	if pattern := d.Get("name_regex").(string); pattern != "" {
+		// ValidateFunc is skipped when the pattern is unknown at plan time, so check it again
+		nameRegex, err = regexp.Compile(pattern)
+		if err != nil {
+			diags := diag.Errorf("invalid name_regex: %v", err)
+			diags[0].AttributePath = cty.GetAttrPath("name_regex")
+			return diags
+		}
+	}
+
+	customerList := []map[string]interface{}{}
+	for _, customer := range *response {
+		if name != "" && customer.Name != name {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(customer.Name) {
+			continue
+		}
+		customerList = append(customerList, map[string]interface{}{
+			"id":        customer.CustomerID,
+			"name":      customer.Name,
+			"leaf":      customer.Leaf,
+			"parent_id": customer.ParentID,
+		})
+	}
+
+	if err := d.Set("customers", customerList); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set customers: %v", err))
+	}
+
+	d.SetId("customers")
+	return nil
+}
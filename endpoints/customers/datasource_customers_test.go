@@ -0,0 +1,87 @@
+// Copyright 2025, Jamf Software LLC.
+package customers_test
+
+import (
+	"regexp"
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCustomersDataSource_basic(t *testing.T) {
+	s := fakejsc.New(t)
+	s.SetCustomers(
+		fakejsc.Customer{ID: "parent", Name: "Parent", Leaf: false},
+		fakejsc.Customer{ID: "child-1", Name: "EMEA Child 1", Leaf: true, ParentID: "parent"},
+		fakejsc.Customer{ID: "child-2", Name: "EMEA Child 2", Leaf: true, ParentID: "parent"},
+		fakejsc.Customer{ID: "child-3", Name: "APAC Child 3", Leaf: true, ParentID: "parent"},
+	)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+data "jsc_customers" "all" {}
+
+data "jsc_customers" "emea" {
+  name_regex = "^EMEA "
+}
+
+data "jsc_customers" "apac" {
+  name = "APAC Child 3"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jsc_customers.all", "customers.#", "4"),
+					resource.TestCheckResourceAttr("data.jsc_customers.all", "customers.0.id", "parent"),
+					resource.TestCheckResourceAttr("data.jsc_customers.all", "customers.0.leaf", "false"),
+					resource.TestCheckResourceAttr("data.jsc_customers.all", "customers.0.parent_id", ""),
+					resource.TestCheckResourceAttr("data.jsc_customers.emea", "customers.#", "2"),
+					resource.TestCheckResourceAttr("data.jsc_customers.emea", "customers.1.id", "child-2"),
+					resource.TestCheckResourceAttr("data.jsc_customers.emea", "customers.1.name", "EMEA Child 2"),
+					resource.TestCheckResourceAttr("data.jsc_customers.emea", "customers.1.leaf", "true"),
+					resource.TestCheckResourceAttr("data.jsc_customers.emea", "customers.1.parent_id", "parent"),
+					resource.TestCheckResourceAttr("data.jsc_customers.apac", "customers.#", "1"),
+					resource.TestCheckResourceAttr("data.jsc_customers.apac", "customers.0.id", "child-3"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCustomersDataSource_invalidRegex(t *testing.T) {
+	s := fakejsc.New(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+data "jsc_customers" "test" {
+  name_regex = "("
+}
+`,
+				ExpectError: regexp.MustCompile(`name_regex`),
+			},
+			{
+				// Not known until apply, so only Read sees the pattern
+				Config: acctest.ProviderConfig(s) + `
+resource "terraform_data" "pattern" {
+  input = "("
+}
+
+data "jsc_customers" "test" {
+  name_regex = terraform_data.pattern.output
+}
+`,
+				ExpectError: regexp.MustCompile(`invalid name_regex`),
+			},
+		},
+	})
+}
@@ -3,10 +3,7 @@ package entra_idp
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -19,6 +16,7 @@ func DataSourceEntraIdps() *schema.Resource {
 		ReadContext: dataSourceEntraIdpsRead,
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.DataSourceCustomerIDSchema(),
 			"connections": {
 				Type:        schema.TypeList,
 				Computed:    true,
@@ -53,40 +51,24 @@ func DataSourceEntraIdps() *schema.Resource {
 }
 
 func dataSourceEntraIdpsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	req, err := http.NewRequest("GET", "https://radar.wandera.com/gate/identity-service/v1/connections", nil)
+	c, err := auth.ClientFor(d, meta)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to build Entra IdP list request: %v", err))
+		return diag.FromErr(err)
 	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("Entra IdP list request failed: %v", err))
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return diag.FromErr(fmt.Errorf("failed to list Entra IdP connections: %s", resp.Status))
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
+	connections, err := listConnections(ctx, c)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to read Entra IdP response: %v", err))
-	}
-
-	var connections []entraConnection
-	if err := json.Unmarshal(body, &connections); err != nil {
-		return diag.FromErr(fmt.Errorf("failed to parse Entra IdP response: %v", err))
+		return diag.FromErr(err)
 	}
 
 	// Filter to only AZURE_END_USER (Entra) connections
 	var entraConnections []map[string]interface{}
-	for _, c := range connections {
-		if c.Type == "AZURE_END_USER" {
+	for _, conn := range connections {
+		if conn.Type == "AZURE_END_USER" {
 			entraConnections = append(entraConnections, map[string]interface{}{
-				"id":    c.ID,
-				"name":  c.Name,
-				"type":  c.Type,
-				"state": c.State,
+				"id":    conn.ID,
+				"name":  conn.Name,
+				"type":  conn.Type,
+				"state": conn.State,
 			})
 		}
 	}
@@ -0,0 +1,45 @@
+// Copyright 2025, Jamf Software LLC.
+package entra_idp_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccEntraIdpsDataSource_basic(t *testing.T) {
+	s := fakejsc.New(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+resource "jsc_oktaidp" "test" {
+  name      = "Okta"
+  orgdomain = "example.okta.com"
+  clientid  = "0oa1example"
+}
+
+resource "jsc_entra_idp" "test" {
+  name = "Entra"
+}
+
+data "jsc_entra_idps" "test" {
+  depends_on = [jsc_oktaidp.test, jsc_entra_idp.test]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					// Only the Entra connection is listed
+					resource.TestCheckResourceAttr("data.jsc_entra_idps.test", "connections.#", "1"),
+					resource.TestCheckResourceAttrPair("data.jsc_entra_idps.test", "connections.0.id", "jsc_entra_idp.test", "id"),
+					resource.TestCheckResourceAttr("data.jsc_entra_idps.test", "connections.0.name", "Entra"),
+				),
+			},
+		},
+	})
+}
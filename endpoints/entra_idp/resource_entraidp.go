@@ -2,14 +2,14 @@
 package entra_idp
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"net/http"
+	"time"
 
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -27,15 +27,22 @@ type entraConsentResponse struct {
 // ResourceEntraIdp returns the schema.Resource for jsc_entra_idp.
 func ResourceEntraIdp() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceEntraIdpCreate,
-		Read:   resourceEntraIdpRead,
-		Update: resourceEntraIdpUpdate,
-		Delete: resourceEntraIdpDelete,
+		CreateContext: resourceEntraIdpCreate,
+		ReadContext:   resourceEntraIdpRead,
+		UpdateContext: resourceEntraIdpUpdate,
+		DeleteContext: resourceEntraIdpDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: auth.ImportCustomerState,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.CustomerIDSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -55,43 +62,37 @@ func ResourceEntraIdp() *schema.Resource {
 	}
 }
 
-func resourceEntraIdpCreate(d *schema.ResourceData, m interface{}) error {
-	// Step 1: Create the Entra connection
-	payload, err := json.Marshal(map[string]string{
-		"type": "AZURE_END_USER",
-		"name": d.Get("name").(string),
-	})
+// listConnections returns every IdP connection of the customer. There is no single-connection GET.
+func listConnections(ctx context.Context, c *auth.Client) ([]entraConnection, error) {
+	connections, err := client.Get[[]entraConnection](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/identity-service/v1/connections"))
 	if err != nil {
-		return fmt.Errorf("failed to marshal jsc_entra_idp payload: %v", err)
+		return nil, fmt.Errorf("failed to list IdP connections: %w", err)
 	}
+	return *connections, nil
+}
 
-	req, err := http.NewRequest("POST", "https://radar.wandera.com/gate/identity-service/v1/connections", bytes.NewBuffer(payload))
-	if err != nil {
-		return fmt.Errorf("failed to build jsc_entra_idp create request: %v", err)
-	}
+// entraIdpAttributePaths points JSC validation errors for the connection request at the
+// attributes they were built from.
+var entraIdpAttributePaths = client.AttributePaths{
+	"name": "name",
+}
 
-	resp, err := auth.MakeRequest(req)
+func resourceEntraIdpCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("jsc_entra_idp create request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("failed to create jsc_entra_idp connection: %s", resp.Status)
+		return diag.FromErr(err)
 	}
-
-	body, err := ioutil.ReadAll(resp.Body)
+	// Step 1: Create the Entra connection
+	connection, err := client.Post[entraConnection](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/identity-service/v1/connections"), map[string]string{
+		"type": "AZURE_END_USER",
+		"name": d.Get("name").(string),
+	})
 	if err != nil {
-		return fmt.Errorf("failed to read jsc_entra_idp create response: %v", err)
-	}
-
-	var connection entraConnection
-	if err := json.Unmarshal(body, &connection); err != nil {
-		return fmt.Errorf("failed to parse jsc_entra_idp create response: %v", err)
+		return client.Diagnostics(err, "failed to create jsc_entra_idp connection", entraIdpAttributePaths)
 	}
 
 	if connection.ID == "" {
-		return fmt.Errorf("jsc_entra_idp was created but API returned an empty ID")
+		return diag.FromErr(fmt.Errorf("jsc_entra_idp was created but API returned an empty ID"))
 	}
 
 	d.SetId(connection.ID)
@@ -99,31 +100,11 @@ func resourceEntraIdpCreate(d *schema.ResourceData, m interface{}) error {
 	// Step 2: Trigger the consent transaction to generate the OAuth URL.
 	// The URL is printed to the console for the admin to complete manually.
 	// It is NOT stored in Terraform state to avoid persisting OAuth tokens.
-	consentReq, err := http.NewRequest("POST",
-		fmt.Sprintf("https://radar.wandera.com/gate/identity-service/v1/connections/%s/consent-transactions", connection.ID),
-		bytes.NewBuffer([]byte("{}")))
-	if err != nil {
-		return fmt.Errorf("failed to build consent transaction request: %v", err)
-	}
-
-	consentResp, err := auth.MakeRequest(consentReq)
+	consentResult, err := client.Post[entraConsentResponse](ctx, c.MakeRequest,
+		client.URL("https://radar.wandera.com/gate/identity-service/v1/connections/{id}/consent-transactions").Param("id", connection.ID),
+		struct{}{})
 	if err != nil {
-		return fmt.Errorf("consent transaction request failed: %v", err)
-	}
-	defer consentResp.Body.Close()
-
-	if consentResp.StatusCode != http.StatusOK && consentResp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("failed to create consent transaction: %s", consentResp.Status)
-	}
-
-	consentBody, err := ioutil.ReadAll(consentResp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read consent transaction response: %v", err)
-	}
-
-	var consentResult entraConsentResponse
-	if err := json.Unmarshal(consentBody, &consentResult); err != nil {
-		return fmt.Errorf("failed to parse consent transaction response: %v", err)
+		return client.Diagnostics(err, "failed to create consent transaction", nil)
 	}
 
 	// Store the consent URL so the admin can retrieve it and complete the OAuth
@@ -133,40 +114,24 @@ func resourceEntraIdpCreate(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceEntraIdpRead(d *schema.ResourceData, m interface{}) error {
-	// No single-resource GET — must filter the connections list by ID.
-	req, err := http.NewRequest("GET", "https://radar.wandera.com/gate/identity-service/v1/connections", nil)
-	if err != nil {
-		return fmt.Errorf("failed to build jsc_entra_idp read request: %v", err)
-	}
-
-	resp, err := auth.MakeRequest(req)
+func resourceEntraIdpRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("jsc_entra_idp read request failed: %v", err)
+		return diag.FromErr(err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to list IdP connections: %s", resp.Status)
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
+	// No single-resource GET — must filter the connections list by ID.
+	connections, err := listConnections(ctx, c)
 	if err != nil {
-		return fmt.Errorf("failed to read jsc_entra_idp list response: %v", err)
-	}
-
-	var connections []entraConnection
-	if err := json.Unmarshal(body, &connections); err != nil {
-		return fmt.Errorf("failed to parse jsc_entra_idp list response: %v", err)
+		return diag.FromErr(err)
 	}
 
-	for _, c := range connections {
-		if c.ID == d.Id() {
-			d.Set("name", c.Name)
-			d.Set("state", c.State)
+	for _, conn := range connections {
+		if conn.ID == d.Id() {
+			d.Set("name", conn.Name)
+			d.Set("state", conn.State)
 			// Clear the consent URL once consent is complete — it is only needed
 			// during the INITIAL/PENDING window and should not persist in state.
-			if c.State == "APPROVED" {
+			if conn.State == "APPROVED" {
 				d.Set("consent_url", "")
 			}
 			return nil
@@ -178,29 +143,21 @@ func resourceEntraIdpRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceEntraIdpUpdate(d *schema.ResourceData, m interface{}) error {
-	if err := resourceEntraIdpDelete(d, m); err != nil {
-		return err
+func resourceEntraIdpUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := resourceEntraIdpDelete(ctx, d, m); diags.HasError() {
+		return diags
 	}
-	return resourceEntraIdpCreate(d, m)
+	return resourceEntraIdpCreate(ctx, d, m)
 }
 
-func resourceEntraIdpDelete(d *schema.ResourceData, m interface{}) error {
-	req, err := http.NewRequest("DELETE",
-		fmt.Sprintf("https://radar.wandera.com/gate/identity-service/v1/connections/%s", d.Id()),
-		nil)
+func resourceEntraIdpDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("failed to build jsc_entra_idp delete request: %v", err)
+		return diag.FromErr(err)
 	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return fmt.Errorf("jsc_entra_idp delete request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to delete jsc_entra_idp: %s", resp.Status)
+	err = client.Delete(ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/identity-service/v1/connections/{id}").Param("id", d.Id()))
+	if err != nil && !client.IsNotFound(err) {
+		return client.Diagnostics(err, "failed to delete jsc_entra_idp", nil)
 	}
 
 	d.SetId("")
@@ -0,0 +1,48 @@
+// Copyright 2025, Jamf Software LLC.
+package entra_idp_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccEntraIdp_basic(t *testing.T) {
+	s := fakejsc.New(t)
+	config := acctest.ProviderConfig(s) + `
+resource "jsc_entra_idp" "test" {
+  name = "Entra"
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		CheckDestroy:             acctest.CheckDestroy(s, fakejsc.Connections, "jsc_entra_idp"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jsc_entra_idp.test", "name", "Entra"),
+					resource.TestCheckResourceAttr("jsc_entra_idp.test", "state", "INITIAL"),
+					resource.TestCheckResourceAttrSet("jsc_entra_idp.test", "consent_url"),
+				),
+			},
+			{
+				ResourceName:      "jsc_entra_idp.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The consent URL is only returned when the connection is created
+				ImportStateVerifyIgnore: []string{"consent_url"},
+			},
+			{
+				Config:             config,
+				Check:              acctest.Disappears(s, fakejsc.Connections, "jsc_entra_idp.test"),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
@@ -3,13 +3,11 @@ package groupedgws
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"strings"
 
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -31,6 +29,7 @@ func DataSourceGroupedGWs() *schema.Resource {
 		ReadContext: dataSourceGroupedGWsRead,
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.DataSourceCustomerIDSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -67,33 +66,17 @@ func DataSourceGroupedGWs() *schema.Resource {
 }
 
 func dataSourceGroupedGWsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	req, err := http.NewRequest("GET", "https://radar.wandera.com/gate/traffic-routing-service/v1/virtual-vpn-routes", nil)
+	c, err := auth.ClientFor(d, meta)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create HTTP request: %v", err))
+		return diag.FromErr(err)
 	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to execute request: %v", err))
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
+	gateways, err := client.Get[[]GroupedGateway](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/traffic-routing-service/v1/virtual-vpn-routes"))
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to read response body: %v", err))
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return diag.FromErr(fmt.Errorf("failed to read grouped gateways: %s - %s", resp.Status, string(body)))
-	}
-
-	var gateways []GroupedGateway
-	if err := json.Unmarshal(body, &gateways); err != nil {
-		return diag.FromErr(fmt.Errorf("failed to parse response: %v", err))
+		return client.Diagnostics(err, "failed to read grouped gateways", nil)
 	}
 
 	searchName := d.Get("name").(string)
-	for _, gw := range gateways {
+	for _, gw := range *gateways {
 		if strings.EqualFold(gw.Name, searchName) {
 			d.SetId(gw.ID)
 			d.Set("name", gw.Name)
@@ -0,0 +1,35 @@
+// Copyright 2025, Jamf Software LLC.
+package groupedgws_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGroupedGWsDataSource_basic(t *testing.T) {
+	s := fakejsc.New(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+data "jsc_groupedgws" "test" {
+  name = "Fake Grouped Gateway"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jsc_groupedgws.test", "id", "ggw-1"),
+					resource.TestCheckResourceAttr("data.jsc_groupedgws.test", "route_ids.#", "2"),
+					resource.TestCheckResourceAttr("data.jsc_groupedgws.test", "recovery_delay_seconds", "60"),
+					resource.TestCheckResourceAttr("data.jsc_groupedgws.test", "routing_strategy", "LATENCY"),
+				),
+			},
+		},
+	})
+}
@@ -4,14 +4,12 @@ package groups
 import (
 	//"bytes"
 	//"encoding/json"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"strings"
 
 	"context"
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -30,6 +28,7 @@ func DataSourceGroups() *schema.Resource {
 		ReadContext: dataSourceGroupsRead,
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.DataSourceCustomerIDSchema(),
 			"devices": {
 				Type:        schema.TypeInt,
 				Computed:    true,
@@ -51,38 +50,18 @@ func DataSourceGroups() *schema.Resource {
 
 // Define the read function for groups
 func dataSourceGroupsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	req, err := http.NewRequest("GET", "https://radar.wandera.com/gate/user-service/user/v3/{customerid}/groups?showDeleted=false", nil)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("error creating http request: %v", err))
-	}
-	resp, err := auth.MakeRequest(req)
-
+	c, err := auth.ClientFor(d, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	defer resp.Body.Close()
-
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return diag.FromErr(fmt.Errorf("failed to read groups info: %s", resp.Status))
-	}
-
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
+	response, err := client.Get[[]Group](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/user-service/user/v3/{customerid}/groups").Query("showDeleted", "false").Customer(client.CustomerPath))
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("error parsing body response: %v", err))
-	}
-
-	// Parse the response JSON
-	var response []Group
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return diag.FromErr(err)
+		return client.Diagnostics(err, "failed to read groups info", nil)
 	}
 
 	// Find group by name (case-insensitive match)
 	searchName := d.Get("name").(string)
-	for _, group := range response {
+	for _, group := range *response {
 		// Skip entries with null group name (ungrouped devices)
 		if group.Group == nil || group.GroupId == nil {
 			continue
@@ -0,0 +1,33 @@
+// Copyright 2025, Jamf Software LLC.
+package groups_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGroupsDataSource_basic(t *testing.T) {
+	s := fakejsc.New(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+data "jsc_groups" "test" {
+  name = "Fake Group"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jsc_groups.test", "id", "group-1"),
+					resource.TestCheckResourceAttr("data.jsc_groups.test", "devices", "3"),
+				),
+			},
+		},
+	})
+}
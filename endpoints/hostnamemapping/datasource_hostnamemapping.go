@@ -2,15 +2,10 @@
 package hostnamemapping
 
 import (
-	//"bytes"
-	//"encoding/json"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"strings"
 
-	"context"
 	"jsctfprovider/internal/auth"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -45,6 +40,7 @@ func DataSourceHostnameMappings() *schema.Resource {
 		ReadContext: dataSourceAllMappingsRead,
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.DataSourceCustomerIDSchema(),
 			"mappings": {
 				Type:        schema.TypeList,
 				Computed:    true,
@@ -74,29 +70,13 @@ func DataSourceHostnameMappings() *schema.Resource {
 }
 
 func dataSourceAllMappingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	req, err := http.NewRequest("GET", "https://radar.wandera.com/gate/dns-zone-management-service/v1/custom-hostname-mappings", nil)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to build hostname mappings list request: %v", err))
-	}
-
-	resp, err := auth.MakeRequest(req)
+	c, err := auth.ClientFor(d, meta)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("hostname mappings list request failed: %v", err))
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return diag.FromErr(fmt.Errorf("failed to list hostname mappings: %s", resp.Status))
+		return diag.FromErr(err)
 	}
-
-	body, err := ioutil.ReadAll(resp.Body)
+	response, err := getAllHostnameMappings(ctx, c)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to read hostname mappings response: %v", err))
-	}
-
-	var response Mappings
-	if err := json.Unmarshal(body, &response); err != nil {
-		return diag.FromErr(fmt.Errorf("failed to parse hostname mappings response: %v", err))
+		return diag.FromErr(err)
 	}
 
 	mappingList := make([]map[string]interface{}, len(response.Mapping))
@@ -121,6 +101,7 @@ func DataSourceHostnameMapping() *schema.Resource {
 		ReadContext: dataSourceMappingsRead,
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.DataSourceCustomerIDSchema(),
 			"hostname": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -158,32 +139,12 @@ func DataSourceHostnameMapping() *schema.Resource {
 
 // Define the read function for routes
 func dataSourceMappingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/dns-zone-management-service/v1/custom-hostname-mappings"), nil)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("error converting making http request body"))
-	}
-	resp, err := auth.MakeRequest((req))
-
+	c, err := auth.ClientFor(d, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	defer resp.Body.Close()
-
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return diag.FromErr(fmt.Errorf("failed to read routes info: %s", resp.Status))
-	}
-
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("error making parsing body response"))
-	}
 
-	// Parse the response JSON
-	var response Mappings
-	err = json.Unmarshal(body, &response)
+	response, err := getAllHostnameMappings(ctx, c)
 	if err != nil {
 		return diag.FromErr(err)
 	}
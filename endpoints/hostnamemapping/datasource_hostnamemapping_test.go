@@ -0,0 +1,53 @@
+// Copyright 2025, Jamf Software LLC.
+package hostnamemapping_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccHostnameMappingDataSources_basic(t *testing.T) {
+	s := fakejsc.New(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+resource "jsc_hostnamemapping" "one" {
+  hostname = "one.example.com"
+  a        = ["10.0.0.1"]
+}
+
+resource "jsc_hostnamemapping" "two" {
+  hostname = "two.example.com"
+  aaaa     = ["fd00::2"]
+
+  # Mappings share one document, so write them one after the other
+  depends_on = [jsc_hostnamemapping.one]
+}
+
+data "jsc_hostnamemapping" "two" {
+  hostname   = "two.example.com"
+  depends_on = [jsc_hostnamemapping.two]
+}
+
+data "jsc_hostnamemappings" "all" {
+  depends_on = [jsc_hostnamemapping.two]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jsc_hostnamemapping.two", "id", "two.example.com"),
+					resource.TestCheckTypeSetElemAttr("data.jsc_hostnamemapping.two", "aaaa.*", "fd00::2"),
+					resource.TestCheckResourceAttr("data.jsc_hostnamemappings.all", "mappings.#", "2"),
+					resource.TestCheckResourceAttr("data.jsc_hostnamemappings.all", "mappings.0.hostname", "one.example.com"),
+				),
+			},
+		},
+	})
+}
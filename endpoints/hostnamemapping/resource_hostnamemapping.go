@@ -2,16 +2,15 @@
 package hostnamemapping
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"strings"
 	"sync"
 
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -24,16 +23,17 @@ var hostnameMappingMu sync.Mutex
 // Define the schema for the Okta resource
 func ResourceHostnameMapping() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceHostnameMappingCreate,
-		Read:   resourceHostnameMappingRead,
-		Update: resourceHostnameMappingUpdate,
-		Delete: resourceHostnameMappingDelete,
+		CreateContext: resourceHostnameMappingCreate,
+		ReadContext:   resourceHostnameMappingRead,
+		UpdateContext: resourceHostnameMappingUpdate,
+		DeleteContext: resourceHostnameMappingDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: auth.ImportCustomerState,
 		},
 
 		// Define the attributes of the okta resource
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.CustomerIDSchema(),
 			"hostname": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -74,53 +74,34 @@ func ResourceHostnameMapping() *schema.Resource {
 
 //a few helper functions
 
-func getAllHostnameMappings() (*Mappings, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/dns-zone-management-service/v1/custom-hostname-mappings"), nil)
-	if err != nil {
-		return nil, (fmt.Errorf("error converting making http request body"))
-	}
-	resp, err := auth.MakeRequest((req))
-
-	if err != nil {
-		return nil, (fmt.Errorf("error making http request"))
-	}
-	defer resp.Body.Close()
-
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return nil, (fmt.Errorf("failed to read routes info: %s", resp.Status))
-	}
-
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, (fmt.Errorf("error making parsing body response"))
-	}
+var hostnameMappingsEndpoint = client.URL("https://radar.wandera.com/gate/dns-zone-management-service/v1/custom-hostname-mappings")
 
-	// Parse the response JSON
-	var response Mappings
-	err = json.Unmarshal(body, &response)
+func getAllHostnameMappings(ctx context.Context, c *auth.Client) (*Mappings, error) {
+	response, err := client.Get[Mappings](ctx, c.MakeRequest, hostnameMappingsEndpoint)
 	if err != nil {
-		return nil, (err)
+		return nil, fmt.Errorf("failed to read hostname mappings: %w", err)
 	}
-
-	return &response, nil
+	return response, nil
 }
 
 // Define the create function for the mapping resource
-func resourceHostnameMappingCreate(d *schema.ResourceData, m interface{}) error {
+func resourceHostnameMappingCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	// Lock to prevent concurrent read-modify-write race conditions
 	hostnameMappingMu.Lock()
 	defer hostnameMappingMu.Unlock()
 
-	response, err := getAllHostnameMappings()
+	response, err := getAllHostnameMappings(ctx, c)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	for _, mapping := range response.Mapping {
 		if strings.EqualFold(mapping.Hostname, d.Get("hostname").(string)) {
-			return (fmt.Errorf("hostname mapping already exists"))
+			return diag.FromErr((fmt.Errorf("hostname mapping already exists")))
 		}
 	}
 
@@ -150,25 +131,10 @@ func resourceHostnameMappingCreate(d *schema.ResourceData, m interface{}) error
 	}
 	response.Mapping = append(response.Mapping, newMapping)
 
-	payload, err := json.Marshal(response)
-	if err != nil {
-		return err
-	}
-
 	// Make a PUT request to update all mappings
-	req, err := http.NewRequest("PUT", ("https://radar.wandera.com/gate/dns-zone-management-service/v1/custom-hostname-mappings"), bytes.NewBuffer(payload))
-	if err != nil {
-		return err
-	}
-	resp, err := auth.MakeRequest((req))
+	err = client.Put(ctx, c.MakeRequest, hostnameMappingsEndpoint, response)
 	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != 201 {
-		return fmt.Errorf("failed to create hostname mapping: %s", resp.Status+" ")
+		return client.Diagnostics(err, "failed to create hostname mapping", nil)
 	}
 
 	d.SetId(d.Get("hostname").(string))
@@ -176,16 +142,22 @@ func resourceHostnameMappingCreate(d *schema.ResourceData, m interface{}) error
 }
 
 // Define the read function for the hostname mapping
-func resourceHostnameMappingRead(d *schema.ResourceData, m interface{}) error {
+func resourceHostnameMappingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// Make a GET request to read the details of mappings
 
-	response, err := getAllHostnameMappings()
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	response, err := getAllHostnameMappings(ctx, c)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
+	// Match on the ID rather than config so the mapping can also be imported by hostname
 	for _, mapping := range response.Mapping {
-		if strings.EqualFold(mapping.Hostname, d.Get("hostname").(string)) {
+		if strings.EqualFold(mapping.Hostname, d.Id()) {
+			d.Set("hostname", mapping.Hostname)
 			d.Set("securedns", mapping.SecureDNS)
 			d.Set("ztna", mapping.ZTNA)
 			// Convert your `A` slice to a set
@@ -195,22 +167,28 @@ func resourceHostnameMappingRead(d *schema.ResourceData, m interface{}) error {
 			aaaaSet := schema.NewSet(schema.HashString, convertStringSliceToInterfaceSet(mapping.AAAA))
 			d.Set("aaaa", aaaaSet)
 			d.SetId(mapping.Hostname) //need to set something for resource to exist
-			break
+			return nil
 		}
 	}
 
+	// Not found - mapping has been removed outside Terraform
+	d.SetId("")
 	return nil
 }
 
 // Define the update function for the hostname resource
-func resourceHostnameMappingUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceHostnameMappingUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	// Lock to prevent concurrent read-modify-write race conditions
 	hostnameMappingMu.Lock()
 	defer hostnameMappingMu.Unlock()
 
-	response, err := getAllHostnameMappings()
+	response, err := getAllHostnameMappings(ctx, c)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Convert schema.TypeSet to []string for A records
@@ -244,27 +222,12 @@ func resourceHostnameMappingUpdate(d *schema.ResourceData, m interface{}) error
 	}
 
 	if !found {
-		return fmt.Errorf("hostname mapping not found for update: %s", d.Id())
-	}
-
-	payload, err := json.Marshal(response)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest("PUT", "https://radar.wandera.com/gate/dns-zone-management-service/v1/custom-hostname-mappings", bytes.NewBuffer(payload))
-	if err != nil {
-		return err
+		return diag.FromErr(fmt.Errorf("hostname mapping not found for update: %s", d.Id()))
 	}
 
-	resp, err := auth.MakeRequest(req)
+	err = client.Put(ctx, c.MakeRequest, hostnameMappingsEndpoint, response)
 	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != 201 {
-		return fmt.Errorf("failed to update hostname mapping: %s", resp.Status)
+		return client.Diagnostics(err, "failed to update hostname mapping", nil)
 	}
 
 	// Update the ID if hostname changed
@@ -273,14 +236,18 @@ func resourceHostnameMappingUpdate(d *schema.ResourceData, m interface{}) error
 }
 
 // Define the delete function for the hostname resource
-func resourceHostnameMappingDelete(d *schema.ResourceData, m interface{}) error {
+func resourceHostnameMappingDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	// Lock to prevent concurrent read-modify-write race conditions
 	hostnameMappingMu.Lock()
 	defer hostnameMappingMu.Unlock()
 
-	response, err := getAllHostnameMappings()
+	response, err := getAllHostnameMappings(ctx, c)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	var filteredMappings []Mapping
 	for _, mapping := range response.Mapping {
@@ -290,25 +257,10 @@ func resourceHostnameMappingDelete(d *schema.ResourceData, m interface{}) error
 	}
 	response.Mapping = filteredMappings
 
-	payload, err := json.Marshal(response)
-	if err != nil {
-		return err
-	}
-
 	// Make a PUT request to update all mappings
-	req, err := http.NewRequest("PUT", ("https://radar.wandera.com/gate/dns-zone-management-service/v1/custom-hostname-mappings"), bytes.NewBuffer(payload))
-	if err != nil {
-		return err
-	}
-	resp, err := auth.MakeRequest((req))
+	err = client.Put(ctx, c.MakeRequest, hostnameMappingsEndpoint, response)
 	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != 201 {
-		return fmt.Errorf("failed to delete hostname mapping: %s", resp.Status+" ")
+		return client.Diagnostics(err, "failed to delete hostname mapping", nil)
 	}
 
 	// Clear the resource ID
@@ -0,0 +1,61 @@
+// Copyright 2025, Jamf Software LLC.
+package hostnamemapping_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccHostnameMapping_basic(t *testing.T) {
+	s := fakejsc.New(t)
+	config := func(a string) string {
+		return acctest.ProviderConfig(s) + `
+resource "jsc_hostnamemapping" "test" {
+  hostname = "intranet.example.com"
+  a        = ["` + a + `"]
+  ztna     = false
+}
+`
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if slices.Contains(s.HostnameMappings(), "intranet.example.com") {
+				return fmt.Errorf("hostname mapping intranet.example.com still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config("10.0.0.1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jsc_hostnamemapping.test", "id", "intranet.example.com"),
+					resource.TestCheckTypeSetElemAttr("jsc_hostnamemapping.test", "a.*", "10.0.0.1"),
+					resource.TestCheckResourceAttr("jsc_hostnamemapping.test", "securedns", "true"),
+					resource.TestCheckResourceAttr("jsc_hostnamemapping.test", "ztna", "false"),
+				),
+			},
+			{
+				Config: config("10.0.0.2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jsc_hostnamemapping.test", "a.#", "1"),
+					resource.TestCheckTypeSetElemAttr("jsc_hostnamemapping.test", "a.*", "10.0.0.2"),
+				),
+			},
+			{
+				ResourceName:      "jsc_hostnamemapping.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
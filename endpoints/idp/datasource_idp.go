@@ -5,10 +5,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -32,6 +32,7 @@ func DataSourceIdpConnection() *schema.Resource {
 		ReadContext: dataSourceIdpConnectionRead,
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.DataSourceCustomerIDSchema(),
 			"connection_id": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -56,40 +57,38 @@ func DataSourceIdpConnection() *schema.Resource {
 	}
 }
 
-// dataSourceIdpConnectionRead calls GET /gate/identity-service/v1/connections,
-// takes the first result, and populates all computed attributes.
-func dataSourceIdpConnectionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	req, err := http.NewRequest("GET", "https://radar.wandera.com/gate/identity-service/v1/connections", nil)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("error building IdP connection request: %w", err))
-	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("error executing IdP connection request: %w", err))
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return diag.FromErr(fmt.Errorf("failed to read IdP connections: %s", resp.Status))
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
+// listIdpConnections returns every IdP connection of the customer.
+// The API may return either a bare array or an object with a "data" key.
+func listIdpConnections(ctx context.Context, c *auth.Client) ([]IdpConnection, error) {
+	body, err := client.Send(ctx, c.MakeRequest, http.MethodGet, client.URL("https://radar.wandera.com/gate/identity-service/v1/connections"), nil)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("error reading IdP connection response body: %w", err))
+		return nil, fmt.Errorf("failed to read IdP connections: %w", err)
 	}
 
-	// The API may return either a bare array or an object with a "data" key.
 	// Attempt bare array first; fall back to the wrapped form.
 	var connections []IdpConnection
 	if err := json.Unmarshal(body, &connections); err != nil {
 		// Try the wrapped form.
 		var wrapped IdpConnectionListResponse
 		if err2 := json.Unmarshal(body, &wrapped); err2 != nil {
-			return diag.FromErr(fmt.Errorf("error parsing IdP connection response: %w (wrapped parse: %v)", err, err2))
+			return nil, fmt.Errorf("error parsing IdP connection response: %w (wrapped parse: %v)", err, err2)
 		}
 		connections = wrapped.Data
 	}
+	return connections, nil
+}
+
+// dataSourceIdpConnectionRead calls GET /gate/identity-service/v1/connections,
+// takes the first result, and populates all computed attributes.
+func dataSourceIdpConnectionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	connections, err := listIdpConnections(ctx, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	if len(connections) == 0 {
 		return diag.FromErr(fmt.Errorf("no IdP connections found on this JSC tenant"))
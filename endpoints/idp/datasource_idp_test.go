@@ -0,0 +1,40 @@
+// Copyright 2025, Jamf Software LLC.
+package idp_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccIdpConnectionDataSource_basic(t *testing.T) {
+	s := fakejsc.New(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+resource "jsc_oktaidp" "test" {
+  name      = "Okta"
+  orgdomain = "example.okta.com"
+  clientid  = "0oa1example"
+}
+
+data "jsc_idp_connection" "test" {
+  depends_on = [jsc_oktaidp.test]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.jsc_idp_connection.test", "connection_id", "jsc_oktaidp.test", "id"),
+					resource.TestCheckResourceAttr("data.jsc_idp_connection.test", "name", "Okta"),
+					resource.TestCheckResourceAttr("data.jsc_idp_connection.test", "type", "OKTA"),
+				),
+			},
+		},
+	})
+}
@@ -2,26 +2,26 @@
 package idp
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"net/http"
+	"context"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // Define the schema for the Okta resource
 func ResourceOktaIdp() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOktaIdpCreate,
-		Read:   resourceOktaIdpRead,
-		Update: resourceOktaIdpUpdate,
-		Delete: resourceOktaIdpDelete,
+		CreateContext: resourceOktaIdpCreate,
+		ReadContext:   resourceOktaIdpRead,
+		UpdateContext: resourceOktaIdpUpdate,
+		DeleteContext: resourceOktaIdpDelete,
 
 		// Define the attributes of the okta resource
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.CustomerIDSchema(),
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -42,9 +42,20 @@ func ResourceOktaIdp() *schema.Resource {
 	}
 }
 
-// Define the create function for the okta resource
-func resourceOktaIdpCreate(d *schema.ResourceData, m interface{}) error {
+// oktaIdpAttributePaths points JSC validation errors for the connection request at the
+// attributes they were built from.
+var oktaIdpAttributePaths = client.AttributePaths{
+	"name":      "name",
+	"orgDomain": "orgdomain",
+	"clientId":  "clientid",
+}
 
+// Define the create function for the okta resource
+func resourceOktaIdpCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Construct the request body
 	vm := map[string]interface{}{
@@ -53,40 +64,10 @@ func resourceOktaIdpCreate(d *schema.ResourceData, m interface{}) error {
 		"clientId":  d.Get("clientid").(string),
 		"type":      "OKTA",
 	}
-	payload, err := json.Marshal(vm)
-	if err != nil {
-		return err
-	}
-
 	// Make a POST request to create a new okta
-	req, err := http.NewRequest("POST", ("https://radar.wandera.com/gate/identity-service/v1/connections"), bytes.NewBuffer(payload))
-	if err != nil {
-		return err
-	}
-	resp, err := auth.MakeRequest((req))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != 201 {
-		return fmt.Errorf("failed to create Okta IDP Connection: %s", resp.Status+" ")
-	}
-
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-
-	// Parse the response JSON
-	var response struct {
-		ID string `json:"id"`
-	}
-	err = json.Unmarshal(body, &response)
+	response, err := client.Post[IdpConnection](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/identity-service/v1/connections"), vm)
 	if err != nil {
-		return err
+		return client.Diagnostics(err, "failed to create Okta IDP Connection", oktaIdpAttributePaths)
 	}
 
 	// Set the resource ID
@@ -99,61 +80,51 @@ func resourceOktaIdpCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 // Define the read function for the Okta resource
-func resourceOktaIdpRead(d *schema.ResourceData, m interface{}) error {
-	// Make a GET request to read the details of an existing Okta IDP
-
-
-
-	req, err := http.NewRequest("GET", ("https://radar.wandera.com/gate/identity-service/v1/connections"), nil)
+func resourceOktaIdpRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	resp, err := auth.MakeRequest((req))
-
+	// There is no single-connection GET, look for ours in the list
+	connections, err := listIdpConnections(ctx, c)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	defer resp.Body.Close()
 
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to read OKTA IDP info: %s", resp.Status)
+	for _, conn := range connections {
+		if conn.ID == d.Id() {
+			d.Set("name", conn.Name)
+			return nil
+		}
 	}
 
+	// Not found - resource has been deleted outside Terraform
+	d.SetId("")
 	return nil
 }
 
-// Define the update function for the Okta  resource NOT IMPLIMENTED
-
-func resourceOktaIdpUpdate(d *schema.ResourceData, m interface{}) error {
-
-	d.Set("requires_replace", true)
-	resourceOktaIdpDelete(d, m)
-	resourceOktaIdpCreate(d, m)
-	return nil
+// Define the update function for the Okta resource. The connection can not be changed in
+// place, so it is replaced; if the create fails the old one is already gone and the error
+// leaves the resource out of state.
+func resourceOktaIdpUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := resourceOktaIdpDelete(ctx, d, m); diags.HasError() {
+		// Nothing changed, keep the previous state rather than the planned one
+		d.Partial(true)
+		return diags
+	}
+	return resourceOktaIdpCreate(ctx, d, m)
 }
 
 // Define the delete function for the Okta resource
-func resourceOktaIdpDelete(d *schema.ResourceData, m interface{}) error {
-	// Make a DELETE request to delete an existing Okta
-
-
-
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("https://radar.wandera.com/gate/identity-service/v1/connections/%s", d.Id()), nil)
+func resourceOktaIdpDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-
-	// Send the request
-	resp, err := auth.MakeRequest((req))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != 204 {
-		return fmt.Errorf("failed to delete OktaIDP: %v %v %v", resp.Status, resp, req)
+	// Make a DELETE request to delete an existing Okta
+	err = client.Delete(ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/identity-service/v1/connections/{id}").Param("id", d.Id()))
+	if err != nil && !client.IsNotFound(err) {
+		return client.Diagnostics(err, "failed to delete Okta IDP Connection", nil)
 	}
 
 	// Clear the resource ID
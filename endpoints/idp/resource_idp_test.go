@@ -0,0 +1,52 @@
+// Copyright 2025, Jamf Software LLC.
+package idp_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccOktaIdp_basic(t *testing.T) {
+	s := fakejsc.New(t)
+	config := func(name string) string {
+		return acctest.ProviderConfig(s) + `
+resource "jsc_oktaidp" "test" {
+  name      = "` + name + `"
+  orgdomain = "example.okta.com"
+  clientid  = "0oa1example"
+}
+`
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		CheckDestroy:             acctest.CheckDestroy(s, fakejsc.Connections, "jsc_oktaidp"),
+		Steps: []resource.TestStep{
+			{
+				Config: config("Okta"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("jsc_oktaidp.test", "id"),
+					resource.TestCheckResourceAttr("jsc_oktaidp.test", "name", "Okta"),
+				),
+			},
+			{
+				// Updates replace the connection
+				Config: config("Okta SSO"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jsc_oktaidp.test", "id", "connection-2"),
+					resource.TestCheckResourceAttr("jsc_oktaidp.test", "name", "Okta SSO"),
+				),
+			},
+			{
+				Config:             config("Okta SSO"),
+				Check:              acctest.Disappears(s, fakejsc.Connections, "jsc_oktaidp.test"),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
@@ -4,14 +4,11 @@ package pagapptemplates
 import (
 	//"bytes"
 	//"encoding/json"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"net/http"
 	"strings"
 
 	"context"
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -50,40 +47,16 @@ func DataSourcePAGAppTemplates() *schema.Resource {
 
 // Define the read function for routes
 func dataSourcePAGAppTemplatesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*auth.Client)
 
-	req, err := http.NewRequest("GET", ("https://api.wandera.com/ztna/v1/app-templates"), nil)
+	response, err := client.Get[[]ResponseItemAppTemplates](ctx, c.MakePAGRequest, client.URL("https://api.wandera.com/ztna/v1/app-templates").Customer(client.NoCustomer))
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("error converting making http request body"))
-	}
-	resp, err := auth.MakePAGRequest((req))
-
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	defer resp.Body.Close()
-
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return diag.FromErr(fmt.Errorf("failed to read app template info: %s", resp.Status))
-	}
-
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("error making parsing body response"))
-	}
-
-	// Parse the response JSON
-
-	var response []ResponseItemAppTemplates
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return diag.FromErr(err)
+		return client.Diagnostics(err, "failed to read app template info", nil)
 	}
 
 	// Find id from the first instance where name contains "the provided name"
 
-	for _, ip := range response {
+	for _, ip := range *response {
 		if strings.Contains(ip.Name, d.Get("name").(string)) {
 			d.SetId(ip.ID)
 			d.Set("hostnames", ip.Hostnames)
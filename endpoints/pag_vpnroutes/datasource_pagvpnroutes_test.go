@@ -0,0 +1,33 @@
+// Copyright 2025, Jamf Software LLC.
+package pagvpnroutes_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPAGVPNRoutesDataSource_basic(t *testing.T) {
+	s := fakejsc.New(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+data "jsc_pag_vpnroutes" "test" {
+  name = "Fake PAG Route"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jsc_pag_vpnroutes.test", "id", "pag-route-1"),
+					resource.TestCheckResourceAttr("data.jsc_pag_vpnroutes.test", "shared", "true"),
+				),
+			},
+		},
+	})
+}
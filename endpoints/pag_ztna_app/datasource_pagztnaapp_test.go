@@ -0,0 +1,43 @@
+// Copyright 2025, Jamf Software LLC.
+package pagztnaapp_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPAGZTNAAppDataSource_basic(t *testing.T) {
+	s := fakejsc.New(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+resource "jsc_pag_ztnaapp" "test" {
+  name        = "Intranet"
+  hostnames   = ["intranet.example.com"]
+  routingtype = "CUSTOM"
+  routingid   = "pag-route-1"
+}
+
+data "jsc_pag_ztnaapp" "test" {
+  name       = jsc_pag_ztnaapp.test.name
+  depends_on = [jsc_pag_ztnaapp.test]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.jsc_pag_ztnaapp.test", "id", "jsc_pag_ztnaapp.test", "id"),
+					resource.TestCheckResourceAttr("data.jsc_pag_ztnaapp.test", "hostnames.0", "intranet.example.com"),
+					resource.TestCheckResourceAttr("data.jsc_pag_ztnaapp.test", "routingtype", "CUSTOM"),
+					resource.TestCheckResourceAttr("data.jsc_pag_ztnaapp.test", "routingid", "pag-route-1"),
+				),
+			},
+		},
+	})
+}
@@ -2,13 +2,11 @@
 package pagztnaapp
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
+	"context"
 	"jsctfprovider/internal/auth"
-	"net/http"
+	"jsctfprovider/internal/client"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -38,10 +36,10 @@ type ResponseItemZTNAApp struct {
 // Define the schema for the ZTNA resource
 func ResourcePAGZTNAApp() *schema.Resource {
 	return &schema.Resource{
-		Create:        resourcePAGZTNAAppCreate,
-		Read:          resourcePAGZTNAAppRead,
-		Update:        resourcePAGZTNAAppUpdate,
-		Delete:        resourcePAGZTNAAppDelete,
+		CreateContext: resourcePAGZTNAAppCreate,
+		ReadContext:   resourcePAGZTNAAppRead,
+		UpdateContext: resourcePAGZTNAAppUpdate,
+		DeleteContext: resourcePAGZTNAAppDelete,
 		CustomizeDiff: validatePAGZTNADataFields,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -156,9 +154,30 @@ func ResourcePAGZTNAApp() *schema.Resource {
 	}
 }
 
-// Define the create function for the ztna resource
-func resourcePAGZTNAAppCreate(d *schema.ResourceData, m interface{}) error {
+// pagZTNAAppAttributePaths points JSC validation errors for the app request at the attributes
+// they were built from.
+var pagZTNAAppAttributePaths = client.AttributePaths{
+	"name":                                         "name",
+	"categoryName":                                 "categoryname",
+	"appTemplateId":                                "apptemplateid",
+	"hostnames":                                    "hostnames.#",
+	"bareIps":                                      "bareips.#",
+	"assignments.inclusions.allUsers":              "assignmentallusers",
+	"assignments.inclusions.groups":                "assignmentgroups.#",
+	"routing.type":                                 "routingtype",
+	"routing.routeId":                              "routingid",
+	"routing.dnsIpResolutionType":                  "routingdnstype",
+	"security.riskControls.enabled":                "securityriskcontrolenabled",
+	"security.riskControls.levelThreshold":         "securityriskcontrolthreshold",
+	"security.riskControls.notificationsEnabled":   "securityriskcontrolnotifications",
+	"security.dohIntegration.blocking":             "securitydohintegrationblocking",
+	"security.dohIntegration.notificationsEnabled": "securitydohintegrationnotifications",
+	"security.deviceManagementBasedAccess.enabled": "securitydevicemanagementbasedaccessenabled",
+	"security.deviceManagementBasedAccess.notificationsEnabled": "securitydevicemanagementbasedaccessnotifications",
+}
 
+// buildPAGZTNAAppRequest builds the app sent on both create and update.
+func buildPAGZTNAAppRequest(d *schema.ResourceData) RequestItemZTNAApp {
 	hostnamesInterface := d.Get("hostnames").([]interface{}) // Get the raw slice of interfaces
 
 	// Now convert each element of the slice to a string
@@ -191,7 +210,7 @@ func resourcePAGZTNAAppCreate(d *schema.ResourceData, m interface{}) error {
 		routingdnstype = ""
 	}
 
-	config := RequestItemZTNAApp{
+	return RequestItemZTNAApp{
 		Name:         d.Get("name").(string),
 		CategoryName: d.Get("categoryname").(string),
 		Assignments: Assignments{
@@ -224,42 +243,18 @@ func resourcePAGZTNAAppCreate(d *schema.ResourceData, m interface{}) error {
 		Hostnames:     hostnames,
 		BareIps:       bareips,
 	}
+}
 
-	payload, err := json.Marshal(config)
-	if err != nil {
-		return err
-	}
-	// Make a POST request to create a new okta
-	req, err := http.NewRequest("POST", ("https://api.wandera.com/ztna/v1/apps"), bytes.NewBuffer(payload))
-	if err != nil {
-		return err
-	}
-	resp, err := auth.MakePAGRequest((req))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != 201 {
-		println(payload)
-		return fmt.Errorf("failed to create PAG ZTNA App: %s", resp.Status+" "+string(payload))
-
-	}
-
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
+// Define the create function for the ztna resource
+func resourcePAGZTNAAppCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*auth.Client)
 
-	// Parse the response JSON
-	var response struct {
+	// Make a POST request to create a new ZTNA app
+	response, err := client.Post[struct {
 		ID string `json:"id"`
-	}
-	err = json.Unmarshal(body, &response)
+	}](ctx, c.MakePAGRequest, client.URL("https://api.wandera.com/ztna/v1/apps").Customer(client.NoCustomer), buildPAGZTNAAppRequest(d))
 	if err != nil {
-		return err
+		return client.Diagnostics(err, "failed to create PAG ZTNA App", pagZTNAAppAttributePaths)
 	}
 
 	// Set the resource ID
@@ -269,36 +264,16 @@ func resourcePAGZTNAAppCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 // Define the read function for the ztna resource
-func resourcePAGZTNAAppRead(d *schema.ResourceData, m interface{}) error {
+func resourcePAGZTNAAppRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*auth.Client)
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://api.wandera.com/ztna/v1/apps/%s", d.Id()), nil)
-
-	if err != nil {
-		return (fmt.Errorf("error converting making http request body"))
+	response, err := client.Get[ResponseItemZTNAApp](ctx, c.MakePAGRequest, client.URL("https://api.wandera.com/ztna/v1/apps/{id}").Param("id", d.Id()).Customer(client.NoCustomer))
+	if client.IsNotFound(err) {
+		d.SetId("")
+		return nil
 	}
-	resp, err := auth.MakePAGRequest((req))
-
-	if err != nil {
-		return (err)
-	}
-	defer resp.Body.Close()
-
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return (fmt.Errorf("failed to read app policy info: %s", resp.Status))
-	}
-
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return (fmt.Errorf("error making parsing body response"))
-	}
-
-	// Parse the response JSON
-	var response ResponseItemZTNAApp
-	err = json.Unmarshal(body, &response)
 	if err != nil {
-		return err
+		return client.Diagnostics(err, "failed to read PAG ZTNA App", nil)
 	}
 
 	d.SetId(response.ID)
@@ -310,38 +285,39 @@ func resourcePAGZTNAAppRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("routingtype", response.Routing.Type)
 	d.Set("routingid", response.Routing.RouteId)
 	d.Set("routingdnstype", response.Routing.DnsIpResolutionType)
+	d.Set("securityriskcontrolenabled", response.Security.RiskControls.Enabled)
+	d.Set("securityriskcontrolthreshold", response.Security.RiskControls.LevelThreshold)
+	d.Set("securityriskcontrolnotifications", response.Security.RiskControls.NotificationsEnabled)
+	d.Set("securitydohintegrationblocking", response.Security.DohIntegration.Blocking)
+	d.Set("securitydohintegrationnotifications", response.Security.DohIntegration.NotificationsEnabled)
+	d.Set("securitydevicemanagementbasedaccessenabled", response.Security.DeviceManagementBasedAccess.Enabled)
+	d.Set("securitydevicemanagementbasedaccessnotifications", response.Security.DeviceManagementBasedAccess.NotificationsEnabled)
+	d.Set("assignmentallusers", response.Assignments.Inclusions.AllUsers)
+	d.Set("assignmentgroups", response.Assignments.Inclusions.Groups)
 
 	return nil
 }
 
-// Define the update function for the ztna  resource NOT IMPLIMENTED
+// Define the update function for the ztna resource. The app is replaced in place so it keeps
+// its ID and users keep access while it changes.
+func resourcePAGZTNAAppUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*auth.Client)
 
-func resourcePAGZTNAAppUpdate(d *schema.ResourceData, m interface{}) error {
+	err := client.Put(ctx, c.MakePAGRequest, client.URL("https://api.wandera.com/ztna/v1/apps/{id}").Param("id", d.Id()).Customer(client.NoCustomer), buildPAGZTNAAppRequest(d))
+	if err != nil {
+		return client.Diagnostics(err, "failed to update PAG ZTNA App", pagZTNAAppAttributePaths)
+	}
 
-	d.Set("requires_replace", true)
-	resourcePAGZTNAAppDelete(d, m)
-	resourcePAGZTNAAppCreate(d, m)
-	return nil
+	return resourcePAGZTNAAppRead(ctx, d, m)
 }
 
 // Define the delete function for the ztna resource
-func resourcePAGZTNAAppDelete(d *schema.ResourceData, m interface{}) error {
-
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("https://api.wandera.com/ztna/v1/apps/%s", d.Id()), nil)
-	if err != nil {
-		return err
-	}
-
-	// Send the request
-	resp, err := auth.MakePAGRequest((req))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+func resourcePAGZTNAAppDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*auth.Client)
 
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != 204 {
-		return fmt.Errorf("failed to delete ZTNA App: %v %v %v", resp.Status, resp, req)
+	err := client.Delete(ctx, c.MakePAGRequest, client.URL("https://api.wandera.com/ztna/v1/apps/{id}").Param("id", d.Id()).Customer(client.NoCustomer))
+	if err != nil && !client.IsNotFound(err) {
+		return client.Diagnostics(err, "failed to delete PAG ZTNA App", nil)
 	}
 
 	// Clear the resource ID
@@ -0,0 +1,89 @@
+// Copyright 2025, Jamf Software LLC.
+package pagztnaapp_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPAGZTNAApp_basic(t *testing.T) {
+	s := fakejsc.New(t)
+	config := func(name, hostnames, groups string) string {
+		return acctest.ProviderConfig(s) + `
+resource "jsc_pag_ztnaapp" "test" {
+  name             = "` + name + `"
+  hostnames        = ` + hostnames + `
+  routingtype      = "CUSTOM"
+  routingid        = "pag-route-1"
+  assignmentgroups = ` + groups + `
+}
+`
+	}
+
+	// Updates are made in place, the app must keep its ID
+	var id string
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		CheckDestroy:             acctest.CheckDestroy(s, fakejsc.PAGApps, "jsc_pag_ztnaapp"),
+		Steps: []resource.TestStep{
+			{
+				Config: config("Intranet", `["intranet.example.com"]`, `["group-1"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("jsc_pag_ztnaapp.test", "id"),
+					func(state *terraform.State) error {
+						id = state.RootModule().Resources["jsc_pag_ztnaapp.test"].Primary.ID
+						return nil
+					},
+					resource.TestCheckResourceAttr("jsc_pag_ztnaapp.test", "name", "Intranet"),
+					resource.TestCheckResourceAttr("jsc_pag_ztnaapp.test", "routingid", "pag-route-1"),
+					resource.TestCheckResourceAttr("jsc_pag_ztnaapp.test", "hostnames.0", "intranet.example.com"),
+					resource.TestCheckResourceAttr("jsc_pag_ztnaapp.test", "assignmentgroups.0", "group-1"),
+				),
+			},
+			{
+				Config: config("Intranet", `["intranet.example.com"]`, `["group-1", "group-2"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("jsc_pag_ztnaapp.test", "id", &id),
+					resource.TestCheckResourceAttr("jsc_pag_ztnaapp.test", "assignmentgroups.#", "2"),
+					resource.TestCheckResourceAttr("jsc_pag_ztnaapp.test", "assignmentgroups.1", "group-2"),
+				),
+			},
+			{
+				Config: config("Intranet apps", `["intranet.example.com", "wiki.example.com"]`, `["group-1", "group-2"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("jsc_pag_ztnaapp.test", "id", &id),
+					resource.TestCheckResourceAttr("jsc_pag_ztnaapp.test", "name", "Intranet apps"),
+					resource.TestCheckResourceAttr("jsc_pag_ztnaapp.test", "hostnames.1", "wiki.example.com"),
+				),
+			},
+			{
+				ResourceName:      "jsc_pag_ztnaapp.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// Assignments changed in the portal show up as drift
+				PreConfig: func() {
+					s.SetField(fakejsc.PAGApps, id, "assignments", map[string]interface{}{
+						"inclusions": map[string]interface{}{"allUsers": true, "groups": []interface{}{}},
+					})
+				},
+				Config:             config("Intranet apps", `["intranet.example.com", "wiki.example.com"]`, `["group-1", "group-2"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config:             config("Intranet apps", `["intranet.example.com", "wiki.example.com"]`, `["group-1", "group-2"]`),
+				Check:              acctest.Disappears(s, fakejsc.PAGApps, "jsc_pag_ztnaapp.test"),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
@@ -2,28 +2,28 @@
 package physical_access
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 )
 
 // ResourceSwiftConnect returns the schema.Resource for the jsc_swiftconnect resource.
 func ResourceSwiftConnect() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceSwiftConnectCreate,
-		Read:   resourceSwiftConnectRead,
-		Update: resourceSwiftConnectUpdate,
-		Delete: resourceSwiftConnectDelete,
+		CreateContext: resourceSwiftConnectCreate,
+		ReadContext:   resourceSwiftConnectRead,
+		UpdateContext: resourceSwiftConnectUpdate,
+		DeleteContext: resourceSwiftConnectDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: auth.ImportCustomerState,
 		},
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.CustomerIDSchema(),
 			"base_url": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -61,8 +61,25 @@ func ResourceSwiftConnect() *schema.Resource {
 	}
 }
 
-func resourceSwiftConnectCreate(d *schema.ResourceData, m interface{}) error {
-	payload, err := json.Marshal(map[string]interface{}{
+// swiftConnectAttributePaths points JSC validation errors for the integration request at the
+// attributes they were built from.
+var swiftConnectAttributePaths = client.AttributePaths{
+	"baseUrl":            "base_url",
+	"applicationId":      "application_id",
+	"origoUuid":          "origo_uuid",
+	"organizationUuid":   "organization_uuid",
+	"riskLevelEnabled":   "risk_level_enabled",
+	"riskLevelThreshold": "risk_level_threshold",
+}
+
+func resourceSwiftConnectCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	response, err := client.Post[struct {
+		ID string `json:"id"`
+	}](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/physical-access-service/v1/integrations/{customerid}").Customer(client.CustomerPath), map[string]interface{}{
 		"baseUrl":            d.Get("base_url").(string),
 		"applicationId":      d.Get("application_id").(string),
 		"origoUuid":          d.Get("origo_uuid").(string),
@@ -71,72 +88,23 @@ func resourceSwiftConnectCreate(d *schema.ResourceData, m interface{}) error {
 		"riskLevelThreshold": d.Get("risk_level_threshold").(string),
 	})
 	if err != nil {
-		return fmt.Errorf("failed to marshal SwiftConnect payload: %v", err)
-	}
-
-	req, err := http.NewRequest("POST", "https://radar.wandera.com/gate/physical-access-service/v1/integrations/{customerid}", bytes.NewBuffer(payload))
-	if err != nil {
-		return fmt.Errorf("failed to build SwiftConnect create request: %v", err)
-	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return fmt.Errorf("SwiftConnect create request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("failed to create SwiftConnect integration: %s", resp.Status)
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read SwiftConnect create response: %v", err)
-	}
-
-	var response struct {
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return fmt.Errorf("failed to parse SwiftConnect create response: %v", err)
+		return client.Diagnostics(err, "failed to create SwiftConnect integration", swiftConnectAttributePaths)
 	}
 
 	if response.ID == "" {
-		return fmt.Errorf("SwiftConnect integration was created but API returned an empty ID")
+		return diag.FromErr(fmt.Errorf("SwiftConnect integration was created but API returned an empty ID"))
 	}
 
 	d.SetId(response.ID)
 	return nil
 }
 
-func resourceSwiftConnectRead(d *schema.ResourceData, m interface{}) error {
-	req, err := http.NewRequest("GET", "https://radar.wandera.com/gate/physical-access-service/v1/integrations/{customerid}", nil)
-	if err != nil {
-		return fmt.Errorf("failed to build SwiftConnect read request: %v", err)
-	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return fmt.Errorf("SwiftConnect read request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	// 404 means no integration exists — tell Terraform to recreate it
-	if resp.StatusCode == http.StatusNotFound {
-		d.SetId("")
-		return nil
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to read SwiftConnect integration: %s", resp.Status)
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
+func resourceSwiftConnectRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("failed to read SwiftConnect read response: %v", err)
+		return diag.FromErr(err)
 	}
-
-	var response struct {
+	response, err := client.Get[struct {
 		ID                 string `json:"id"`
 		BaseURL            string `json:"baseUrl"`
 		ApplicationID      string `json:"applicationId"`
@@ -144,9 +112,14 @@ func resourceSwiftConnectRead(d *schema.ResourceData, m interface{}) error {
 		OrganizationUUID   string `json:"organizationUuid"`
 		RiskLevelEnabled   bool   `json:"riskLevelEnabled"`
 		RiskLevelThreshold string `json:"riskLevelThreshold"`
+	}](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/physical-access-service/v1/integrations/{customerid}").Customer(client.CustomerPath))
+	// 404 means no integration exists — tell Terraform to recreate it
+	if client.IsNotFound(err) {
+		d.SetId("")
+		return nil
 	}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return fmt.Errorf("failed to parse SwiftConnect read response: %v", err)
+	if err != nil {
+		return client.Diagnostics(err, "failed to read SwiftConnect integration", nil)
 	}
 
 	d.Set("base_url", response.BaseURL)
@@ -159,28 +132,22 @@ func resourceSwiftConnectRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceSwiftConnectUpdate(d *schema.ResourceData, m interface{}) error {
-	if err := resourceSwiftConnectDelete(d, m); err != nil {
-		return err
+func resourceSwiftConnectUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := resourceSwiftConnectDelete(ctx, d, m); diags.HasError() {
+		return diags
 	}
-	return resourceSwiftConnectCreate(d, m)
+	return resourceSwiftConnectCreate(ctx, d, m)
 }
 
-func resourceSwiftConnectDelete(d *schema.ResourceData, m interface{}) error {
-	// Delete uses v2 endpoint with integration id (not customerId) — intentional API asymmetry
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("https://radar.wandera.com/gate/physical-access-service/v2/integrations/%s", d.Id()), nil)
+func resourceSwiftConnectDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("failed to build SwiftConnect delete request: %v", err)
+		return diag.FromErr(err)
 	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return fmt.Errorf("SwiftConnect delete request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to delete SwiftConnect integration: %s", resp.Status)
+	// Delete uses v2 endpoint with integration id (not customerId) — intentional API asymmetry
+	err = client.Delete(ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/physical-access-service/v2/integrations/{id}").Param("id", d.Id()))
+	if err != nil && !client.IsNotFound(err) {
+		return client.Diagnostics(err, "failed to delete SwiftConnect integration", nil)
 	}
 
 	d.SetId("")
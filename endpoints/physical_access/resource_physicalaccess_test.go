@@ -0,0 +1,56 @@
+// Copyright 2025, Jamf Software LLC.
+package physical_access_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccSwiftConnect_basic(t *testing.T) {
+	s := fakejsc.New(t)
+	config := func(riskLevelEnabled string) string {
+		return acctest.ProviderConfig(s) + `
+resource "jsc_swiftconnect" "test" {
+  base_url           = "https://api.swiftconnect.example.com"
+  application_id     = "application-id"
+  origo_uuid         = "origo-uuid"
+  risk_level_enabled = ` + riskLevelEnabled + `
+}
+`
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		CheckDestroy:             acctest.CheckDestroy(s, fakejsc.Integrations, "jsc_swiftconnect"),
+		Steps: []resource.TestStep{
+			{
+				Config: config("false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("jsc_swiftconnect.test", "id"),
+					resource.TestCheckResourceAttr("jsc_swiftconnect.test", "base_url", "https://api.swiftconnect.example.com"),
+					resource.TestCheckResourceAttr("jsc_swiftconnect.test", "risk_level_threshold", "HIGH"),
+				),
+			},
+			{
+				// Updates replace the integration, as only one may exist per customer
+				Config: config("true"),
+				Check:  resource.TestCheckResourceAttr("jsc_swiftconnect.test", "risk_level_enabled", "true"),
+			},
+			{
+				ResourceName:      "jsc_swiftconnect.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:             config("true"),
+				Check:              acctest.Disappears(s, fakejsc.Integrations, "jsc_swiftconnect.test"),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
@@ -4,14 +4,12 @@ package routes
 import (
 	//"bytes"
 	//"encoding/json"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"strings"
 
 	"context"
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -32,6 +30,7 @@ func DataSourceRoutes() *schema.Resource {
 		ReadContext: dataSourceRoutesRead,
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.DataSourceCustomerIDSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -58,35 +57,19 @@ func DataSourceRoutes() *schema.Resource {
 
 // Define the read function for routes
 func dataSourceRoutesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	req, err := http.NewRequest("GET", "https://radar.wandera.com/gate/traffic-routing-service/v2/vpn-routes?view=deployments", nil)
+	c, err := auth.ClientFor(d, meta)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create HTTP request: %v", err))
+		return diag.FromErr(err)
 	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to execute request: %v", err))
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
+	routes, err := client.Get[[]Route](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/traffic-routing-service/v2/vpn-routes").Query("view", "deployments"))
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to read response body: %v", err))
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return diag.FromErr(fmt.Errorf("failed to read routes info: %s - %s", resp.Status, string(body)))
-	}
-
-	var routes []Route
-	if err := json.Unmarshal(body, &routes); err != nil {
-		return diag.FromErr(fmt.Errorf("failed to parse response: %v", err))
+		return client.Diagnostics(err, "failed to read routes info", nil)
 	}
 
 	routeName := d.Get("name").(string)
 	found := false
 
-	for _, route := range routes {
+	for _, route := range *routes {
 		if strings.Contains(route.Name, routeName) {
 			d.SetId(route.ID)
 			d.Set("shared", route.Shared)
@@ -0,0 +1,34 @@
+// Copyright 2025, Jamf Software LLC.
+package routes_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccRoutesDataSource_basic(t *testing.T) {
+	s := fakejsc.New(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+data "jsc_routes" "test" {
+  name = "Fake Route London"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jsc_routes.test", "id", "route-1"),
+					resource.TestCheckResourceAttr("data.jsc_routes.test", "datacenter", "lon"),
+					resource.TestCheckResourceAttr("data.jsc_routes.test", "shared", "true"),
+				),
+			},
+		},
+	})
+}
@@ -2,15 +2,16 @@
 package securepolicy
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 )
 
 // securePolicyThreat represents a single threat category entry in the secure policy payload.
@@ -46,7 +47,8 @@ type securePolicyPayload struct {
 	GroupPolicyOverrides      interface{}            `json:"groupPolicyOverrides"`
 }
 
-const securePolicyBaseURL = "https://radar.wandera.com/gate/secure-policy-service/v1/secure-policies/customers/{customerid}"
+// securePolicyEndpoint is the policy of the customer, which the service takes in the path.
+var securePolicyEndpoint = client.URL("https://radar.wandera.com/gate/secure-policy-service/v1/secure-policies/customers/{customerid}").Customer(client.CustomerPath)
 
 // validSeverities is the set of accepted severity strings.
 var validSeverities = []string{"HIGHEST", "HIGH", "MEDIUM", "LOW", "LOWEST", "INFO"}
@@ -54,15 +56,16 @@ var validSeverities = []string{"HIGHEST", "HIGH", "MEDIUM", "LOW", "LOWEST", "IN
 // ResourceSecurePolicy returns the schema.Resource for jsc_secure_policy.
 func ResourceSecurePolicy() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceSecurePolicyCreate,
-		Read:   resourceSecurePolicyRead,
-		Update: resourceSecurePolicyUpdate,
-		Delete: resourceSecurePolicyDelete,
+		CreateContext: resourceSecurePolicyCreate,
+		ReadContext:   resourceSecurePolicyRead,
+		UpdateContext: resourceSecurePolicyUpdate,
+		DeleteContext: resourceSecurePolicyDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: auth.ImportCustomerState,
 		},
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.CustomerIDSchema(),
 			// Each exposed override follows the pattern:
 			//   <threat_category_id_lowercase>_severity
 			// Add additional overrides here as needed in the future.
@@ -413,58 +416,41 @@ func ResourceSecurePolicy() *schema.Resource {
 	}
 }
 
-// getPolicy fetches the current secure policy from the API and returns the raw body bytes.
-func getPolicy() ([]byte, error) {
-	req, err := http.NewRequest("GET", securePolicyBaseURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build jsc_secure_policy GET request: %v", err)
-	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return nil, fmt.Errorf("jsc_secure_policy GET request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("jsc_secure_policy GET returned unexpected status: %s", resp.Status)
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read jsc_secure_policy GET response body: %v", err)
-	}
-
-	return body, nil
+// getPolicy fetches the current secure policy from the API.
+func getPolicy(ctx context.Context, c *auth.Client) (*securePolicyPayload, error) {
+	return client.Get[securePolicyPayload](ctx, c.MakeRequest, securePolicyEndpoint)
 }
 
 // applyOverrides mutates the ThreatCategories raw JSON in place, applying any severity
 // overrides specified in the Terraform config.  All other fields in every threat entry
-// are preserved exactly as received from the API.
-func applyOverrides(raw json.RawMessage, overrides map[string]string) (json.RawMessage, error) {
+// are preserved exactly as received from the API. The returned paths point JSC errors for an
+// overridden threat, which JSC names by its index in the list, at its *_severity attribute.
+func applyOverrides(raw json.RawMessage, overrides map[string]string) (json.RawMessage, client.AttributePaths, error) {
 	// Unmarshal into a slice of generic maps so every field is preserved.
 	var threats []map[string]interface{}
 	if err := json.Unmarshal(raw, &threats); err != nil {
-		return nil, fmt.Errorf("failed to parse threatCategories: %v", err)
+		return nil, nil, fmt.Errorf("failed to parse threatCategories: %v", err)
 	}
 
+	paths := client.AttributePaths{}
 	for i, threat := range threats {
 		id, _ := threat["threatCategoryId"].(string)
 		newSeverity, ok := overrides[id]
 		if !ok {
 			continue
 		}
+		paths[fmt.Sprintf("threatCategories[%d]", i)] = strings.ToLower(id) + "_severity"
 
 		// Navigate action → reportingPolicy → severity, creating intermediate maps
 		// if they are unexpectedly missing so we never panic.
 		action, ok := threat["action"].(map[string]interface{})
 		if !ok {
-			return nil, fmt.Errorf("jsc_secure_policy: threat %q has unexpected 'action' structure", id)
+			return nil, nil, fmt.Errorf("jsc_secure_policy: threat %q has unexpected 'action' structure", id)
 		}
 
 		reportingPolicy, ok := action["reportingPolicy"].(map[string]interface{})
 		if !ok {
-			return nil, fmt.Errorf("jsc_secure_policy: threat %q has unexpected 'action.reportingPolicy' structure", id)
+			return nil, nil, fmt.Errorf("jsc_secure_policy: threat %q has unexpected 'action.reportingPolicy' structure", id)
 		}
 
 		reportingPolicy["severity"] = newSeverity
@@ -475,49 +461,28 @@ func applyOverrides(raw json.RawMessage, overrides map[string]string) (json.RawM
 
 	updated, err := json.Marshal(threats)
 	if err != nil {
-		return nil, fmt.Errorf("failed to re-marshal threatCategories after applying overrides: %v", err)
+		return nil, nil, fmt.Errorf("failed to re-marshal threatCategories after applying overrides: %v", err)
 	}
 
-	return updated, nil
+	return updated, paths, nil
 }
 
 // putPolicy applies the provided severity overrides to the current policy and PUTs it back.
-func putPolicy(overrides map[string]string) error {
-	body, err := getPolicy()
+// Errors JSC reports for a threat category point at its *_severity attribute.
+func putPolicy(ctx context.Context, c *auth.Client, overrides map[string]string, summary string) diag.Diagnostics {
+	payload, err := getPolicy(ctx, c)
 	if err != nil {
-		return err
+		return client.Diagnostics(err, "failed to read jsc_secure_policy", nil)
 	}
 
-	var payload securePolicyPayload
-	if err := json.Unmarshal(body, &payload); err != nil {
-		return fmt.Errorf("failed to parse jsc_secure_policy response: %v", err)
-	}
-
-	updatedThreats, err := applyOverrides(payload.ThreatCategories, overrides)
+	updatedThreats, paths, err := applyOverrides(payload.ThreatCategories, overrides)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	payload.ThreatCategories = updatedThreats
 
-	putBody, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal jsc_secure_policy PUT payload: %v", err)
-	}
-
-	req, err := http.NewRequest("PUT", securePolicyBaseURL, bytes.NewBuffer(putBody))
-	if err != nil {
-		return fmt.Errorf("failed to build jsc_secure_policy PUT request: %v", err)
-	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return fmt.Errorf("jsc_secure_policy PUT request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		respBody, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("jsc_secure_policy PUT returned unexpected status: %s — %s", resp.Status, string(respBody))
+	if err := client.Put(ctx, c.MakeRequest, securePolicyEndpoint, payload); err != nil {
+		return client.Diagnostics(err, summary, paths)
 	}
 
 	return nil
@@ -578,9 +543,13 @@ func buildOverrides(d *schema.ResourceData) map[string]string {
 	}
 }
 
-func resourceSecurePolicyCreate(d *schema.ResourceData, m interface{}) error {
-	if err := putPolicy(buildOverrides(d)); err != nil {
-		return err
+func resourceSecurePolicyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if diags := putPolicy(ctx, c, buildOverrides(d), "failed to create jsc_secure_policy"); diags.HasError() {
+		return diags
 	}
 
 	// Singleton: use a fixed string as the resource ID since there is exactly
@@ -589,20 +558,19 @@ func resourceSecurePolicyCreate(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceSecurePolicyRead(d *schema.ResourceData, m interface{}) error {
-	body, err := getPolicy()
+func resourceSecurePolicyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-
-	var payload securePolicyPayload
-	if err := json.Unmarshal(body, &payload); err != nil {
-		return fmt.Errorf("failed to parse jsc_secure_policy read response: %v", err)
+	payload, err := getPolicy(ctx, c)
+	if err != nil {
+		return client.Diagnostics(err, "failed to read jsc_secure_policy", nil)
 	}
 
 	var threats []map[string]interface{}
 	if err := json.Unmarshal(payload.ThreatCategories, &threats); err != nil {
-		return fmt.Errorf("failed to parse threatCategories on read: %v", err)
+		return diag.FromErr(fmt.Errorf("failed to parse threatCategories on read: %v", err))
 	}
 
 	for _, threat := range threats {
@@ -612,394 +580,394 @@ func resourceSecurePolicyRead(d *schema.ResourceData, m interface{}) error {
 		case "ACCESS_PHISHING_HOST":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("access_phishing_host_severity", severity); err != nil {
-				return fmt.Errorf("failed to set access_phishing_host_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set access_phishing_host_severity in state: %v", err))
 			}
 		case "APP_LEAK_CREDIT_CARD":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_leak_credit_card_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_leak_credit_card_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_leak_credit_card_severity in state: %v", err))
 			}
 		case "APP_LEAK_PASSWORD":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_leak_password_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_leak_password_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_leak_password_severity in state: %v", err))
 			}
 		case "APP_LEAK_EMAIL":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_leak_email_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_leak_email_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_leak_email_severity in state: %v", err))
 			}
 		case "APP_LEAK_USERID":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_leak_userid_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_leak_userid_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_leak_userid_severity in state: %v", err))
 			}
 		case "APP_LEAK_LOCATION":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_leak_location_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_leak_location_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_leak_location_severity in state: %v", err))
 			}
 		case "RESOURCE_LEAK_CREDIT_CARD":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("resource_leak_credit_card_severity", severity); err != nil {
-				return fmt.Errorf("failed to set resource_leak_credit_card_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set resource_leak_credit_card_severity in state: %v", err))
 			}
 		case "RESOURCE_LEAK_PASSWORD":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("resource_leak_password_severity", severity); err != nil {
-				return fmt.Errorf("failed to set resource_leak_password_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set resource_leak_password_severity in state: %v", err))
 			}
 		case "RESOURCE_LEAK_EMAIL":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("resource_leak_email_severity", severity); err != nil {
-				return fmt.Errorf("failed to set resource_leak_email_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set resource_leak_email_severity in state: %v", err))
 			}
 		case "RESOURCE_LEAK_USERID":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("resource_leak_userid_severity", severity); err != nil {
-				return fmt.Errorf("failed to set resource_leak_userid_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set resource_leak_userid_severity in state: %v", err))
 			}
 		case "RESOURCE_LEAK_LOCATION":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("resource_leak_location_severity", severity); err != nil {
-				return fmt.Errorf("failed to set resource_leak_location_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set resource_leak_location_severity in state: %v", err))
 			}
 		case "ACCESS_BAD_HOST":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("access_bad_host_severity", severity); err != nil {
-				return fmt.Errorf("failed to set access_bad_host_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set access_bad_host_severity in state: %v", err))
 			}
 		case "ACCESS_CRYPTOJACKING_HOST":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("access_cryptojacking_host_severity", severity); err != nil {
-				return fmt.Errorf("failed to set access_cryptojacking_host_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set access_cryptojacking_host_severity in state: %v", err))
 			}
 		case "ACCESS_SPAM_HOST":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("access_spam_host_severity", severity); err != nil {
-				return fmt.Errorf("failed to set access_spam_host_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set access_spam_host_severity in state: %v", err))
 			}
 		case "RISKY_APP_DOWNLOAD":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("risky_app_download_severity", severity); err != nil {
-				return fmt.Errorf("failed to set risky_app_download_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set risky_app_download_severity in state: %v", err))
 			}
 		case "APP_MALICIOUS_APP_IN_INVENTORY":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_malicious_app_in_inventory_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_malicious_app_in_inventory_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_malicious_app_in_inventory_severity in state: %v", err))
 			}
 		case "APP_SPYWARE_APP_IN_INVENTORY":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_spyware_app_in_inventory_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_spyware_app_in_inventory_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_spyware_app_in_inventory_severity in state: %v", err))
 			}
 		case "APP_TROJAN_MALWARE_APP_IN_INVENTORY":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_trojan_malware_app_in_inventory_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_trojan_malware_app_in_inventory_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_trojan_malware_app_in_inventory_severity in state: %v", err))
 			}
 		case "APP_RANSOMWARE_APP_IN_INVENTORY":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_ransomware_app_in_inventory_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_ransomware_app_in_inventory_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_ransomware_app_in_inventory_severity in state: %v", err))
 			}
 		case "APP_BANKER_MALWARE_APP_IN_INVENTORY":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_banker_malware_app_in_inventory_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_banker_malware_app_in_inventory_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_banker_malware_app_in_inventory_severity in state: %v", err))
 			}
 		case "APP_SMS_MALWARE_APP_IN_INVENTORY":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_sms_malware_app_in_inventory_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_sms_malware_app_in_inventory_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_sms_malware_app_in_inventory_severity in state: %v", err))
 			}
 		case "APP_ADWARE_APP_IN_INVENTORY":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_adware_app_in_inventory_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_adware_app_in_inventory_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_adware_app_in_inventory_severity in state: %v", err))
 			}
 		case "APP_ROOTING_MALWARE_APP_IN_INVENTORY":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_rooting_malware_app_in_inventory_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_rooting_malware_app_in_inventory_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_rooting_malware_app_in_inventory_severity in state: %v", err))
 			}
 		case "APP_POTENTIALLY_UNWANTED_APP_IN_INVENTORY":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_potentially_unwanted_app_in_inventory_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_potentially_unwanted_app_in_inventory_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_potentially_unwanted_app_in_inventory_severity in state: %v", err))
 			}
 		case "APP_ADMIN_APP_IN_INVENTORY":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_admin_app_in_inventory_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_admin_app_in_inventory_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_admin_app_in_inventory_severity in state: %v", err))
 			}
 		case "APP_SIDE_LOADED_APP_IN_INVENTORY":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_side_loaded_app_in_inventory_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_side_loaded_app_in_inventory_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_side_loaded_app_in_inventory_severity in state: %v", err))
 			}
 		case "APP_THIRD_PARTY_APP_STORES_IN_INVENTORY":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_third_party_app_stores_in_inventory_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_third_party_app_stores_in_inventory_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_third_party_app_stores_in_inventory_severity in state: %v", err))
 			}
 		case "APP_VULNERABLE_APP_IN_INVENTORY":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("app_vulnerable_app_in_inventory_severity", severity); err != nil {
-				return fmt.Errorf("failed to set app_vulnerable_app_in_inventory_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set app_vulnerable_app_in_inventory_severity in state: %v", err))
 			}
 		case "CERTIFICATE_SSL_TRUST_COMPROMISE":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("certificate_ssl_trust_compromise_severity", severity); err != nil {
-				return fmt.Errorf("failed to set certificate_ssl_trust_compromise_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set certificate_ssl_trust_compromise_severity in state: %v", err))
 			}
 		case "NETWORK_ACCESS_POINT_SSL_MITM_TRUSTED_VALID_CERT":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("network_access_point_ssl_mitm_trusted_valid_cert_severity", severity); err != nil {
-				return fmt.Errorf("failed to set network_access_point_ssl_mitm_trusted_valid_cert_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set network_access_point_ssl_mitm_trusted_valid_cert_severity in state: %v", err))
 			}
 		case "NETWORK_ACCESS_POINT_SSL_MITM_UNTRUSTED_VALID_CERT":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("network_access_point_ssl_mitm_untrusted_valid_cert_severity", severity); err != nil {
-				return fmt.Errorf("failed to set network_access_point_ssl_mitm_untrusted_valid_cert_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set network_access_point_ssl_mitm_untrusted_valid_cert_severity in state: %v", err))
 			}
 		case "NETWORK_ACCESS_POINT_SSL_STRIP_MITM":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("network_access_point_ssl_strip_mitm_severity", severity); err != nil {
-				return fmt.Errorf("failed to set network_access_point_ssl_strip_mitm_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set network_access_point_ssl_strip_mitm_severity in state: %v", err))
 			}
 		case "RISKY_HOTSPOT":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("risky_hotspot_severity", severity); err != nil {
-				return fmt.Errorf("failed to set risky_hotspot_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set risky_hotspot_severity in state: %v", err))
 			}
 		case "OS_JAILBREAK":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("os_jailbreak_severity", severity); err != nil {
-				return fmt.Errorf("failed to set os_jailbreak_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set os_jailbreak_severity in state: %v", err))
 			}
 		case "OS_OUTDATED_OS":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("os_outdated_os_severity", severity); err != nil {
-				return fmt.Errorf("failed to set os_outdated_os_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set os_outdated_os_severity in state: %v", err))
 			}
 		case "OS_OUTDATED_OS_LOW":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("os_outdated_os_low_severity", severity); err != nil {
-				return fmt.Errorf("failed to set os_outdated_os_low_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set os_outdated_os_low_severity in state: %v", err))
 			}
 		case "OS_OUT_OF_DATE_OS":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("os_out_of_date_os_severity", severity); err != nil {
-				return fmt.Errorf("failed to set os_out_of_date_os_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set os_out_of_date_os_severity in state: %v", err))
 			}
 		case "DEVICE_APP_INACTIVITY":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("device_app_inactivity_severity", severity); err != nil {
-				return fmt.Errorf("failed to set device_app_inactivity_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set device_app_inactivity_severity in state: %v", err))
 			}
 		case "DEVICE_STORAGE_ENCRYPTION_DISABLED":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("device_storage_encryption_disabled_severity", severity); err != nil {
-				return fmt.Errorf("failed to set device_storage_encryption_disabled_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set device_storage_encryption_disabled_severity in state: %v", err))
 			}
 		case "DEVICE_LOCK_SCREEN_DISABLED":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("device_lock_screen_disabled_severity", severity); err != nil {
-				return fmt.Errorf("failed to set device_lock_screen_disabled_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set device_lock_screen_disabled_severity in state: %v", err))
 			}
 		case "IOS_PROFILE":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("ios_profile_severity", severity); err != nil {
-				return fmt.Errorf("failed to set ios_profile_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set ios_profile_severity in state: %v", err))
 			}
 		case "DEVICE_MISSING_ANDROID_SECURITY_PATCHES":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("device_missing_android_security_patches_severity", severity); err != nil {
-				return fmt.Errorf("failed to set device_missing_android_security_patches_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set device_missing_android_security_patches_severity in state: %v", err))
 			}
 		case "DEVICE_UNKNOWN_SOURCES_ENABLED":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("device_unknown_sources_enabled_severity", severity); err != nil {
-				return fmt.Errorf("failed to set device_unknown_sources_enabled_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set device_unknown_sources_enabled_severity in state: %v", err))
 			}
 		case "DEVICE_USB_APP_VERIFICATION_DISABLED":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("device_usb_app_verification_disabled_severity", severity); err != nil {
-				return fmt.Errorf("failed to set device_usb_app_verification_disabled_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set device_usb_app_verification_disabled_severity in state: %v", err))
 			}
 		case "DEVICE_USER_PASSWORD_DISABLED":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("device_user_password_disabled_severity", severity); err != nil {
-				return fmt.Errorf("failed to set device_user_password_disabled_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set device_user_password_disabled_severity in state: %v", err))
 			}
 		case "DEVICE_DEVELOPER_MODE_ENABLED":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("device_developer_mode_enabled_severity", severity); err != nil {
-				return fmt.Errorf("failed to set device_developer_mode_enabled_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set device_developer_mode_enabled_severity in state: %v", err))
 			}
 		case "DEVICE_USB_DEBUGGING_ENABLED":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("device_usb_debugging_enabled_severity", severity); err != nil {
-				return fmt.Errorf("failed to set device_usb_debugging_enabled_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set device_usb_debugging_enabled_severity in state: %v", err))
 			}
 		case "DEVICE_ANTIVIRUS_DISABLED":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("device_antivirus_disabled_severity", severity); err != nil {
-				return fmt.Errorf("failed to set device_antivirus_disabled_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set device_antivirus_disabled_severity in state: %v", err))
 			}
 		case "DEVICE_FIREWALL_DISABLED":
 			severity, err := extractSeverity(threat, id)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if err := d.Set("device_firewall_disabled_severity", severity); err != nil {
-				return fmt.Errorf("failed to set device_firewall_disabled_severity in state: %v", err)
+				return diag.FromErr(fmt.Errorf("failed to set device_firewall_disabled_severity in state: %v", err))
 			}
 		}
 		// Add additional case blocks here as more overrides are introduced.
@@ -1008,14 +976,18 @@ func resourceSecurePolicyRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceSecurePolicyUpdate(d *schema.ResourceData, m interface{}) error {
-	if err := putPolicy(buildOverrides(d)); err != nil {
-		return err
+func resourceSecurePolicyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
+	if err != nil {
+		return diag.FromErr(err)
 	}
-	return resourceSecurePolicyRead(d, m)
+	if diags := putPolicy(ctx, c, buildOverrides(d), "failed to update jsc_secure_policy"); diags.HasError() {
+		return diags
+	}
+	return resourceSecurePolicyRead(ctx, d, m)
 }
 
-func resourceSecurePolicyDelete(d *schema.ResourceData, m interface{}) error {
+func resourceSecurePolicyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// "Delete" restores the managed overrides to their tenant defaults.
 	defaults := map[string]string{
 		"ACCESS_PHISHING_HOST":                                "HIGHEST",
@@ -1069,8 +1041,12 @@ func resourceSecurePolicyDelete(d *schema.ResourceData, m interface{}) error {
 		"DEVICE_FIREWALL_DISABLED":                            "MEDIUM",
 	}
 
-	if err := putPolicy(defaults); err != nil {
-		return err
+	c, err := auth.ClientFor(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if diags := putPolicy(ctx, c, defaults, "failed to reset jsc_secure_policy"); diags.HasError() {
+		return diags
 	}
 
 	d.SetId("")
@@ -0,0 +1,89 @@
+// Copyright 2025, Jamf Software LLC.
+package securepolicy_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccSecurePolicy_basic(t *testing.T) {
+	s := fakejsc.New(t)
+	config := func(severity string) string {
+		return acctest.ProviderConfig(s) + `
+resource "jsc_secure_policy" "test" {
+  os_jailbreak_severity  = "` + severity + `"
+  risky_hotspot_severity = "LOW"
+}
+`
+	}
+	checkSeverity := func(id, want string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			if got := s.ThreatSeverity(id); got != want {
+				return fmt.Errorf("%s severity is %q, want %q", id, got, want)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		// Destroying the policy restores the tenant defaults
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			checkSeverity("OS_JAILBREAK", "HIGHEST"),
+			checkSeverity("RISKY_HOTSPOT", "MEDIUM"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: config("HIGH"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jsc_secure_policy.test", "id", "secure_policy"),
+					resource.TestCheckResourceAttr("jsc_secure_policy.test", "os_jailbreak_severity", "HIGH"),
+					resource.TestCheckResourceAttr("jsc_secure_policy.test", "access_phishing_host_severity", "HIGHEST"),
+					checkSeverity("OS_JAILBREAK", "HIGH"),
+					checkSeverity("RISKY_HOTSPOT", "LOW"),
+				),
+			},
+			{
+				Config: config("LOWEST"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jsc_secure_policy.test", "os_jailbreak_severity", "LOWEST"),
+					checkSeverity("OS_JAILBREAK", "LOWEST"),
+				),
+			},
+			{
+				ResourceName:      "jsc_secure_policy.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccSecurePolicy_rejectedThreat(t *testing.T) {
+	s := fakejsc.New(t)
+	s.LockThreat("OS_JAILBREAK")
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+resource "jsc_secure_policy" "test" {
+  os_jailbreak_severity = "LOW"
+}
+`,
+				// The error names the threat by index; the diagnostic points at its attribute
+				ExpectError: regexp.MustCompile(`(?s)severity of OS_JAILBREAK cannot be changed.*os_jailbreak_severity`),
+			},
+		},
+	})
+}
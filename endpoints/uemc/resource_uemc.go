@@ -2,16 +2,17 @@
 package uemc
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"log"
-	"net/http"
+	"context"
 
 	"jsctfprovider/internal/auth"
-
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"jsctfprovider/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Define a struct matching the response structure
@@ -21,176 +22,167 @@ type ConfigsResponse struct {
 	} `json:"configs"`
 }
 
-// Define the schema for the UEMC resource
-func ResourceUEMC() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceUEMCCreate,
-		Read:   resourceUEMCRead,
-		Update: resourceUEMCUpdate,
-		Delete: resourceUEMCDelete,
-
-		// Define the attributes of the okta resource
-		Schema: map[string]*schema.Schema{
-			"domain": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Full domain path of Jamf Pro instance.",
+// NewResource returns the jsc_uemc resource. It is served by the framework provider, under the
+// same schema the SDK version had so existing state reads as is.
+func NewResource() resource.Resource {
+	return &uemcResource{}
+}
+
+type uemcResource struct {
+	client *auth.Client
+}
+
+type uemcModel struct {
+	ID           types.String `tfsdk:"id"`
+	CustomerID   types.String `tfsdk:"customer_id"`
+	Domain       types.String `tfsdk:"domain"`
+	ClientSecret types.String `tfsdk:"clientsecret"`
+	ClientID     types.String `tfsdk:"clientid"`
+}
+
+var _ resource.ResourceWithConfigure = (*uemcResource)(nil)
+
+func (r *uemcResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_uemc"
+}
+
+// Schema has every attribute replace the connection, as JSC can not change one in place.
+func (r *uemcResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	replace := []planmodifier.String{stringplanmodifier.RequiresReplace()}
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				Description:   "The ID of this resource.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
-			"clientsecret": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Sensitive:   true,
-				Description: "Client Secret of Jamf Pro API Integration.",
+			"customer_id": auth.CustomerIDAttribute(),
+			"domain": schema.StringAttribute{
+				Required:      true,
+				Description:   "Full domain path of Jamf Pro instance.",
+				PlanModifiers: replace,
 			},
-			"clientid": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Client ID of Jamf Pro API Integration.",
+			"clientsecret": schema.StringAttribute{
+				Required:      true,
+				Sensitive:     true,
+				Description:   "Client Secret of Jamf Pro API Integration.",
+				PlanModifiers: replace,
+			},
+			"clientid": schema.StringAttribute{
+				Required:      true,
+				Description:   "Client ID of Jamf Pro API Integration.",
+				PlanModifiers: replace,
 			},
-			// Add more attributes as needed
 		},
 	}
 }
 
-// Define the create function for the UEMC resource
-func resourceUEMCCreate(d *schema.ResourceData, m interface{}) error {
-
-	// Construct the request body
-	vm := map[string]interface{}{
-		"url":          d.Get("domain").(string),
-		"authStrategy": "JAMF_PRO_OAUTH",
-		"deviceSyncAuth": map[string]string{
-			"clientId":     d.Get("clientid").(string),
-			"clientSecret": d.Get("clientsecret").(string),
-		},
-		"isoCountry": "us",
-		"vendor":     "JAMF_PRO",
+func (r *uemcResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Not configured yet when Terraform only validates the configuration
+	if req.ProviderData == nil {
+		return
 	}
+	r.client = req.ProviderData.(*auth.Client)
+}
 
-	/*vm := map[string]interface{}{
-		"domain":       d.Get("domain").(string),
-		"clientsecret": d.Get("clientsecret").(string),
-		"clientId":     d.Get("clientid").(string),
-	}*/
+// uemcAttributePaths points JSC validation errors for the connection request at the attributes
+// they were built from.
+var uemcAttributePaths = client.AttributePaths{
+	"url":                         "domain",
+	"deviceSyncAuth.clientId":     "clientid",
+	"deviceSyncAuth.clientSecret": "clientsecret",
+}
 
-	payload, err := json.Marshal(vm)
-	if err != nil {
-		return err
-	}
+// clientFor returns the provider client scoped to the customer_id of model, if any.
+func (r *uemcResource) clientFor(model uemcModel) (*auth.Client, error) {
+	return r.client.ForCustomer(model.CustomerID.ValueString())
+}
 
-	// Make a POST request to create a new uemc
-	req, err := http.NewRequest("POST", fmt.Sprintf("https://radar.wandera.com/gate/connector-service/v2/config/emm-server"), bytes.NewBuffer(payload))
-	if err != nil {
-		return err
+func (r *uemcResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan uemcModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	resp, err := auth.MakeRequest((req))
+	c, err := r.clientFor(plan)
 	if err != nil {
-		return err
+		resp.Diagnostics.AddAttributeError(path.Root("customer_id"), err.Error(), "")
+		return
 	}
-	defer resp.Body.Close()
 
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != 200 {
-		return fmt.Errorf("failed to create UEMC Connection: %s", resp.Status+" "+string(payload))
-	}
-
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return err
+	vm := map[string]interface{}{
+		"url":          plan.Domain.ValueString(),
+		"authStrategy": "JAMF_PRO_OAUTH",
+		"deviceSyncAuth": map[string]string{
+			"clientId":     plan.ClientID.ValueString(),
+			"clientSecret": plan.ClientSecret.ValueString(),
+		},
+		"isoCountry": "us",
+		"vendor":     "JAMF_PRO",
 	}
 
-	// Parse the response JSON
-	var response struct {
+	// Make a POST request to create a new uemc
+	response, err := client.Post[struct {
 		ID string `json:"id"`
-	}
-	err = json.Unmarshal(body, &response)
+	}](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/connector-service/v2/config/emm-server"), vm)
 	if err != nil {
-		return err
+		resp.Diagnostics.Append(client.FrameworkDiagnostics(err, "failed to create UEMC Connection", uemcAttributePaths)...)
+		return
 	}
 
-	// Set the resource ID... apparently we can have more than one UEMC connection now!
-	d.SetId(response.ID)
-
-	// Set the resource ID
-	//d.SetId("example-vm-id")
-
-	return nil
+	// More than one UEMC connection is allowed, each is known by its own ID
+	plan.ID = types.StringValue(response.ID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
-// Define the read function for the Okta resource
-func resourceUEMCRead(d *schema.ResourceData, m interface{}) error {
-	// Make a GET request to read the details of an existing Okta IDP
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/connector-service/v2/config"), nil)
-	if err != nil {
-		return err
+func (r *uemcResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state uemcModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	resp, err := auth.MakeRequest((req))
-
-	//resp, err := http.Get(fmt.Sprintf("https://radar.wandera.com/gate/identity-service/v1/connections?customerId=993ae0ee-4bd8-4325-bc5d-1db0ea45b4f6&type=OKTA"))
+	c, err := r.clientFor(state)
 	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to read UEMC info: %s", resp.Status)
+		resp.Diagnostics.AddAttributeError(path.Root("customer_id"), err.Error(), "")
+		return
 	}
 
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
+	// Make a GET request to list the UEMC connections and look for ours
+	configsResp, err := client.Get[ConfigsResponse](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/connector-service/v2/config"))
 	if err != nil {
-		return err
-	}
-
-	// Parse the response JSON and extract the ID
-	var configsResp ConfigsResponse
-	if err := json.Unmarshal(body, &configsResp); err != nil {
-		return err
+		resp.Diagnostics.Append(client.FrameworkDiagnostics(err, "failed to read UEMC info", nil)...)
+		return
 	}
-
-	if len(configsResp.Configs) == 0 {
-		return fmt.Errorf("no configs found in response")
+	for _, config := range configsResp.Configs {
+		if config.ID == state.ID.ValueString() {
+			return
+		}
 	}
 
-	return nil
+	// Not found - resource has been deleted outside Terraform
+	resp.State.RemoveResource(ctx)
 }
 
-// Define the update function for the UEMC - needs to be replace completely
-func resourceUEMCUpdate(d *schema.ResourceData, m interface{}) error {
-	d.Set("requires_replace", true)
-	resourceUEMCDelete(d, m)
-	resourceUEMCCreate(d, m)
-
-	return nil
+// Update is never called, every attribute requires replacement.
+func (r *uemcResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.AddError("UEMC Connection can not be updated in place", "every attribute of jsc_uemc requires replacement")
 }
 
-// Define the delete function for the Okta resource
-func resourceUEMCDelete(d *schema.ResourceData, m interface{}) error {
-	// Make a DELETE request to delete an existing UEMC
-	//First we need to get the config ID of UEMC... we'll assume it's the first one for now.
-	id := d.Id() // Get the current resource ID
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("https://radar.wandera.com/gate/connector-service/v2/config/%s", id), nil)
-	if err != nil {
-		return err
+func (r *uemcResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state uemcModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-
-	// Send the request
-	resp, err := auth.MakeRequest((req))
+	c, err := r.clientFor(state)
 	if err != nil {
-		return err
+		resp.Diagnostics.AddAttributeError(path.Root("customer_id"), err.Error(), "")
+		return
 	}
-	defer resp.Body.Close()
 
-	// Check the response status code - continue anyway to clear state
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != 204 {
-		log.Printf("[WARN] failed to delete UEMC (status %s), removing from state anyway", resp.Status)
+	// Make a DELETE request to delete an existing UEMC, one that is already gone is deleted too
+	err = client.Delete(ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/connector-service/v2/config/{id}").Param("id", state.ID.ValueString()))
+	if err != nil && !client.IsNotFound(err) {
+		resp.Diagnostics.Append(client.FrameworkDiagnostics(err, "failed to delete UEMC Connection", nil)...)
 	}
-
-	// Clear the resource ID
-	d.SetId("")
-
-	return nil
 }
@@ -0,0 +1,188 @@
+// Copyright 2025, Jamf Software LLC.
+package uemc_test
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccUEMC_basic(t *testing.T) {
+	s := fakejsc.New(t)
+	config := acctest.ProviderConfig(s) + `
+resource "jsc_uemc" "test" {
+  domain       = "https://example.jamfcloud.com"
+  clientid     = "client-id"
+  clientsecret = "client-secret"
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		CheckDestroy:             acctest.CheckDestroy(s, fakejsc.UEMConfigs, "jsc_uemc"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("jsc_uemc.test", "id"),
+					resource.TestCheckResourceAttr("jsc_uemc.test", "domain", "https://example.jamfcloud.com"),
+				),
+			},
+			{
+				Config:             config,
+				Check:              acctest.Disappears(s, fakejsc.UEMConfigs, "jsc_uemc.test"),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccUEMC_readOnly(t *testing.T) {
+	s := fakejsc.New(t)
+	config := func(providerConfig, domain string) string {
+		return providerConfig + `
+resource "jsc_uemc" "test" {
+  domain       = "` + domain + `"
+  clientid     = "client-id"
+  clientsecret = "client-secret"
+}
+`
+	}
+	// The connection created in the first step must survive the read only steps
+	exists := func(*terraform.State) error {
+		if !s.Has(fakejsc.UEMConfigs, "uemc-1") {
+			return fmt.Errorf("uemc-1 was deleted")
+		}
+		return nil
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		CheckDestroy:             acctest.CheckDestroy(s, fakejsc.UEMConfigs, "jsc_uemc"),
+		Steps: []resource.TestStep{
+			{
+				Config: config(acctest.ProviderConfig(s), "https://example.jamfcloud.com"),
+				Check:  resource.TestCheckResourceAttr("jsc_uemc.test", "id", "uemc-1"),
+			},
+			{
+				Config:      config(acctest.ReadOnlyProviderConfig(s), "https://other.jamfcloud.com"),
+				ExpectError: regexp.MustCompile(`configured with read_only`),
+			},
+			{
+				Config:      config(acctest.ReadOnlyProviderConfig(s), "https://example.jamfcloud.com"),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`configured with read_only`),
+			},
+			{
+				// Still in state with the same ID, so nothing was dropped without being deleted
+				Config: config(acctest.ProviderConfig(s), "https://example.jamfcloud.com"),
+				Check: resource.ComposeTestCheckFunc(
+					exists,
+					resource.TestCheckResourceAttr("jsc_uemc.test", "id", "uemc-1"),
+				),
+			},
+		},
+	})
+}
+
+// jsc_uemc moved from terraform-plugin-sdk to terraform-plugin-framework. State written by the
+// SDK version must read back unchanged and plan no changes for the same configuration.
+func TestUEMC_sdkState(t *testing.T) {
+	tests := map[string]string{
+		"sdk":                    `{"id":"%s","customer_id":null,"domain":"https://example.jamfcloud.com","clientid":"client-id","clientsecret":"client-secret"}`,
+		"sdk before customer_id": `{"id":"%s","domain":"https://example.jamfcloud.com","clientid":"client-id","clientsecret":"client-secret"}`,
+	}
+	for name, rawState := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			s := fakejsc.New(t)
+			id := s.Add(fakejsc.UEMConfigs, map[string]interface{}{"url": "https://example.jamfcloud.com"})
+			server := acctest.ProviderServer(t, s)
+
+			schemaResp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			objectType := schemaResp.ResourceSchemas["jsc_uemc"].ValueType()
+
+			upgradeResp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+				TypeName: "jsc_uemc",
+				Version:  0,
+				RawState: &tfprotov6.RawState{JSON: []byte(fmt.Sprintf(rawState, id))},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, d := range upgradeResp.Diagnostics {
+				t.Fatalf("UpgradeResourceState: %s: %s", d.Summary, d.Detail)
+			}
+
+			readResp, err := server.ReadResource(ctx, &tfprotov6.ReadResourceRequest{TypeName: "jsc_uemc", CurrentState: upgradeResp.UpgradedState})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, d := range readResp.Diagnostics {
+				t.Fatalf("ReadResource: %s: %s", d.Summary, d.Detail)
+			}
+			state, err := readResp.NewState.Unmarshal(objectType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tftypes.NewValue(objectType, map[string]tftypes.Value{
+				"id":           tftypes.NewValue(tftypes.String, id),
+				"customer_id":  tftypes.NewValue(tftypes.String, nil),
+				"domain":       tftypes.NewValue(tftypes.String, "https://example.jamfcloud.com"),
+				"clientid":     tftypes.NewValue(tftypes.String, "client-id"),
+				"clientsecret": tftypes.NewValue(tftypes.String, "client-secret"),
+			})
+			if !state.Equal(want) {
+				t.Fatalf("state after read = %s, want %s", state, want)
+			}
+
+			// Terraform proposes the prior state when the configuration did not change
+			config, err := tfprotov6.NewDynamicValue(objectType, tftypes.NewValue(objectType, map[string]tftypes.Value{
+				"id":           tftypes.NewValue(tftypes.String, nil),
+				"customer_id":  tftypes.NewValue(tftypes.String, nil),
+				"domain":       tftypes.NewValue(tftypes.String, "https://example.jamfcloud.com"),
+				"clientid":     tftypes.NewValue(tftypes.String, "client-id"),
+				"clientsecret": tftypes.NewValue(tftypes.String, "client-secret"),
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			planResp, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+				TypeName:         "jsc_uemc",
+				PriorState:       readResp.NewState,
+				ProposedNewState: readResp.NewState,
+				Config:           &config,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, d := range planResp.Diagnostics {
+				t.Fatalf("PlanResourceChange: %s: %s", d.Summary, d.Detail)
+			}
+			if len(planResp.RequiresReplace) > 0 {
+				t.Errorf("plan replaces the connection because of %v", planResp.RequiresReplace)
+			}
+			planned, err := planResp.PlannedState.Unmarshal(objectType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !planned.Equal(want) {
+				t.Errorf("planned state = %s, want %s", planned, want)
+			}
+		})
+	}
+}
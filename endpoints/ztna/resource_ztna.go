@@ -2,26 +2,25 @@
 package ztna
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"net/http"
+	"context"
 
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // Define the schema for the ZTNA resource
 func Resourceztna() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceztnaCreate,
-		Read:   resourceztnaRead,
-		Update: resourceztnaUpdate,
-		Delete: resourceztnaDelete,
+		CreateContext: resourceztnaCreate,
+		ReadContext:   resourceztnaRead,
+		UpdateContext: resourceztnaUpdate,
+		DeleteContext: resourceztnaDelete,
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.CustomerIDSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -48,8 +47,21 @@ func Resourceztna() *schema.Resource {
 	}
 }
 
+// ztnaAttributePaths points JSC validation errors for the app request at the attributes they
+// were built from.
+var ztnaAttributePaths = client.AttributePaths{
+	"name":            "name",
+	"type":            "type",
+	"hostnames":       "hostnames.#",
+	"routing.routeId": "routeid",
+}
+
 // Define the create function for the ZTNA resource
-func resourceztnaCreate(d *schema.ResourceData, m interface{}) error {
+func resourceztnaCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	hostnames := d.Get("hostnames").([]interface{})
 	var hostnamesStrings []string
 	for _, h := range hostnames {
@@ -93,36 +105,11 @@ func resourceztnaCreate(d *schema.ResourceData, m interface{}) error {
 		},
 	}
 
-	payload, err := json.Marshal(app)
-	if err != nil {
-		return fmt.Errorf("failed to marshal ZTNA app payload: %v", err)
-	}
-
-	req, err := http.NewRequest("POST", "https://radar.wandera.com/gate/traffic-routing-service/v1/apps", bytes.NewBuffer(payload))
-	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %v", err)
-	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return fmt.Errorf("failed to execute request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("failed to create ZTNA app: %s - %s", resp.Status, string(body))
-	}
-
-	var response struct {
+	response, err := client.Post[struct {
 		ID string `json:"id"`
-	}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return fmt.Errorf("failed to parse response: %v", err)
+	}](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/traffic-routing-service/v1/apps"), app)
+	if err != nil {
+		return client.Diagnostics(err, "failed to create ZTNA app", ztnaAttributePaths)
 	}
 
 	d.SetId(response.ID)
@@ -131,33 +118,12 @@ func resourceztnaCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 // Define the read function for the ZTNA resource
-func resourceztnaRead(d *schema.ResourceData, m interface{}) error {
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/traffic-routing-service/v1/apps/%s", d.Id()), nil)
-	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %v", err)
-	}
-
-	resp, err := auth.MakeRequest(req)
+func resourceztnaRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %v", err)
-	}
-
-	if resp.StatusCode == http.StatusNotFound {
-		d.SetId("")
-		return nil
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to read ZTNA app: %s - %s", resp.Status, string(body))
+		return diag.FromErr(err)
 	}
-
-	var app struct {
+	app, err := client.Get[struct {
 		ID        string   `json:"id"`
 		Name      string   `json:"name"`
 		Type      string   `json:"type"`
@@ -165,9 +131,13 @@ func resourceztnaRead(d *schema.ResourceData, m interface{}) error {
 		Routing   struct {
 			RouteID string `json:"routeId"`
 		} `json:"routing"`
+	}](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/traffic-routing-service/v1/apps/{id}").Param("id", d.Id()))
+	if client.IsNotFound(err) {
+		d.SetId("")
+		return nil
 	}
-	if err := json.Unmarshal(body, &app); err != nil {
-		return fmt.Errorf("failed to parse response: %v", err)
+	if err != nil {
+		return client.Diagnostics(err, "failed to read ZTNA app", nil)
 	}
 
 	d.Set("name", app.Name)
@@ -179,7 +149,11 @@ func resourceztnaRead(d *schema.ResourceData, m interface{}) error {
 }
 
 // Define the update function for the ZTNA resource
-func resourceztnaUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceztnaUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	hostnames := d.Get("hostnames").([]interface{})
 	var hostnamesStrings []string
 	for _, h := range hostnames {
@@ -224,54 +198,23 @@ func resourceztnaUpdate(d *schema.ResourceData, m interface{}) error {
 		},
 	}
 
-	payload, err := json.Marshal(app)
-	if err != nil {
-		return fmt.Errorf("failed to marshal ZTNA app payload: %v", err)
-	}
-
-	req, err := http.NewRequest("PUT", fmt.Sprintf("https://radar.wandera.com/gate/traffic-routing-service/v1/apps/%s", d.Id()), bytes.NewBuffer(payload))
-	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %v", err)
-	}
-
-	resp, err := auth.MakeRequest(req)
+	err = client.Put(ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/traffic-routing-service/v1/apps/{id}").Param("id", d.Id()), app)
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to update ZTNA app: %s - %s", resp.Status, string(body))
+		return client.Diagnostics(err, "failed to update ZTNA app", ztnaAttributePaths)
 	}
 
-	return resourceztnaRead(d, m)
+	return resourceztnaRead(ctx, d, m)
 }
 
 // Define the delete function for the ZTNA resource
-func resourceztnaDelete(d *schema.ResourceData, m interface{}) error {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("https://radar.wandera.com/gate/traffic-routing-service/v1/apps/%s", d.Id()), nil)
+func resourceztnaDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %v", err)
+		return diag.FromErr(err)
 	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return fmt.Errorf("failed to execute request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to delete ZTNA app: %s - %s", resp.Status, string(body))
+	err = client.Delete(ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/traffic-routing-service/v1/apps/{id}").Param("id", d.Id()))
+	if err != nil && !client.IsNotFound(err) {
+		return client.Diagnostics(err, "failed to delete ZTNA app", nil)
 	}
 
 	d.SetId("")
@@ -0,0 +1,47 @@
+// Copyright 2025, Jamf Software LLC.
+package ztna_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccZTNA_basic(t *testing.T) {
+	s := fakejsc.New(t)
+	config := func(name string, hostnames string) string {
+		return acctest.ProviderConfig(s) + `
+resource "jsc_ztna" "test" {
+  name      = "` + name + `"
+  routeid   = "route-1"
+  hostnames = ` + hostnames + `
+}
+`
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		CheckDestroy:             acctest.CheckDestroy(s, fakejsc.Apps, "jsc_ztna"),
+		Steps: []resource.TestStep{
+			{
+				Config: config("Intranet", `["intranet.example.com"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("jsc_ztna.test", "id"),
+					resource.TestCheckResourceAttr("jsc_ztna.test", "name", "Intranet"),
+					resource.TestCheckResourceAttr("jsc_ztna.test", "hostnames.#", "1"),
+				),
+			},
+			{
+				Config: config("Intranet apps", `["intranet.example.com", "wiki.example.com"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jsc_ztna.test", "name", "Intranet apps"),
+					resource.TestCheckResourceAttr("jsc_ztna.test", "hostnames.#", "2"),
+				),
+			},
+		},
+	})
+}
@@ -3,14 +3,12 @@ package ztna_app
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 )
 
 type appTemplateResponse struct {
@@ -25,6 +23,7 @@ func DataSourceAppTemplate() *schema.Resource {
 		ReadContext: dataSourceAppTemplateRead,
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.DataSourceCustomerIDSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -46,33 +45,17 @@ func DataSourceAppTemplate() *schema.Resource {
 }
 
 func dataSourceAppTemplateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	req, err := http.NewRequest("GET", "https://radar.wandera.com/gate/traffic-routing-service/v1/app-templates", nil)
+	c, err := auth.ClientFor(d, meta)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to build app templates request: %v", err))
+		return diag.FromErr(err)
 	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("app templates request failed: %v", err))
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return diag.FromErr(fmt.Errorf("failed to list app templates: %s", resp.Status))
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
+	templates, err := client.Get[[]appTemplateResponse](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/traffic-routing-service/v1/app-templates"))
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to read app templates response: %v", err))
-	}
-
-	var templates []appTemplateResponse
-	if err := json.Unmarshal(body, &templates); err != nil {
-		return diag.FromErr(fmt.Errorf("failed to parse app templates response: %v", err))
+		return client.Diagnostics(err, "failed to list app templates", nil)
 	}
 
 	name := d.Get("name").(string)
-	for _, t := range templates {
+	for _, t := range *templates {
 		if t.Name == name {
 			d.SetId(t.ID)
 			d.Set("name", t.Name)
@@ -0,0 +1,33 @@
+// Copyright 2025, Jamf Software LLC.
+package ztna_app_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccAppTemplateDataSource_basic(t *testing.T) {
+	s := fakejsc.New(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+data "jsc_app_template" "test" {
+  name = "Fake Template"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jsc_app_template.test", "id", "template-1"),
+					resource.TestCheckResourceAttr("data.jsc_app_template.test", "hostnames.#", "2"),
+				),
+			},
+		},
+	})
+}
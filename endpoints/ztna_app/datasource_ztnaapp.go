@@ -3,14 +3,12 @@ package ztna_app
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 )
 
 // DataSourceAccessPolicies returns the schema.Resource for listing all jsc_access_policy resources.
@@ -19,6 +17,7 @@ func DataSourceAccessPolicies() *schema.Resource {
 		ReadContext: dataSourceAccessPoliciesRead,
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.DataSourceCustomerIDSchema(),
 			"policies": {
 				Type:        schema.TypeList,
 				Computed:    true,
@@ -53,30 +52,15 @@ func DataSourceAccessPolicies() *schema.Resource {
 }
 
 func dataSourceAccessPoliciesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	req, err := http.NewRequest("GET", "https://radar.wandera.com/gate/traffic-routing-service/v1/apps", nil)
+	c, err := auth.ClientFor(d, meta)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to build access policies list request: %v", err))
+		return diag.FromErr(err)
 	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("access policies list request failed: %v", err))
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return diag.FromErr(fmt.Errorf("failed to list access policies: %s", resp.Status))
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
+	response, err := client.Get[[]ztnaAppResponse](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/traffic-routing-service/v1/apps"))
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to read access policies response: %v", err))
-	}
-
-	var policies []ztnaAppResponse
-	if err := json.Unmarshal(body, &policies); err != nil {
-		return diag.FromErr(fmt.Errorf("failed to parse access policies response: %v", err))
+		return client.Diagnostics(err, "failed to list access policies", nil)
 	}
+	policies := *response
 
 	policyList := make([]map[string]interface{}, len(policies))
 	for i, policy := range policies {
@@ -0,0 +1,40 @@
+// Copyright 2025, Jamf Software LLC.
+package ztna_app_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccAccessPoliciesDataSource_basic(t *testing.T) {
+	s := fakejsc.New(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+resource "jsc_access_policy" "test" {
+  name      = "Intranet"
+  hostnames = ["intranet.example.com"]
+  routingid = "route-1"
+}
+
+data "jsc_access_policies" "test" {
+  depends_on = [jsc_access_policy.test]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jsc_access_policies.test", "policies.#", "1"),
+					resource.TestCheckResourceAttrPair("data.jsc_access_policies.test", "policies.0.id", "jsc_access_policy.test", "id"),
+					resource.TestCheckResourceAttr("data.jsc_access_policies.test", "policies.0.name", "Intranet"),
+				),
+			},
+		},
+	})
+}
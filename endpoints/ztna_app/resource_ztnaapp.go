@@ -2,14 +2,13 @@
 package ztna_app
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/client"
 )
 
 type ztnaAppInclusions struct {
@@ -93,6 +92,32 @@ func toStringSlice(in []interface{}) []string {
 	return out
 }
 
+// ztnaAppAttributePaths points JSC validation errors for the app request at the attributes
+// they were built from.
+var ztnaAppAttributePaths = client.AttributePaths{
+	"name":                                 "name",
+	"type":                                 "type",
+	"categoryName":                         "categoryname",
+	"appTemplateId":                        "app_template_id",
+	"hostnames":                            "hostnames.#",
+	"bareIps":                              "bareips.#",
+	"assignments.inclusions.allUsers":      "assignmentallusers",
+	"assignments.inclusions.groups":        "assignmentgroups.#",
+	"routing.type":                         "routingtype",
+	"routing.routeId":                      "routingid",
+	"routing.dnsIpResolutionType":          "routingdnstype",
+	"security.riskControls.enabled":        "securityriskcontrolenabled",
+	"security.riskControls.levelThreshold": "securityriskcontrolthreshold",
+	"security.riskControls.notificationsEnabled":                "securityriskcontrolnotifications",
+	"security.dohIntegration.blocking":                          "securitydohintegrationblocking",
+	"security.dohIntegration.notificationsEnabled":              "securitydohintegrationnotifications",
+	"security.deviceManagementBasedAccess.enabled":              "securitydevicemanagementbasedaccessenabled",
+	"security.deviceManagementBasedAccess.notificationsEnabled": "securitydevicemanagementbasedaccessnotifications",
+	"groupOverrides.routingOverrides.groupIds":                  "group_routing_overrides.#.group_ids.#",
+	"groupOverrides.routingOverrides.routing.type":              "group_routing_overrides.#.routing_type",
+	"groupOverrides.routingOverrides.routing.routeId":           "group_routing_overrides.#.routing_id",
+}
+
 func buildZTNAAppRequest(d *schema.ResourceData) ztnaAppRequest {
 	routingdnstype := d.Get("routingdnstype").(string)
 	if d.Get("routingtype").(string) == "DIRECT" {
@@ -161,15 +186,16 @@ func buildZTNAAppRequest(d *schema.ResourceData) ztnaAppRequest {
 // ResourceZTNAApp returns the schema.Resource for jsc_access_policy.
 func ResourceZTNAApp() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceZTNAAppCreate,
-		Read:   resourceZTNAAppRead,
-		Update: resourceZTNAAppUpdate,
-		Delete: resourceZTNAAppDelete,
+		CreateContext: resourceZTNAAppCreate,
+		ReadContext:   resourceZTNAAppRead,
+		UpdateContext: resourceZTNAAppUpdate,
+		DeleteContext: resourceZTNAAppDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: auth.ImportCustomerState,
 		},
 
 		Schema: map[string]*schema.Schema{
+			"customer_id": auth.CustomerIDSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -302,76 +328,38 @@ func ResourceZTNAApp() *schema.Resource {
 	}
 }
 
-func resourceZTNAAppCreate(d *schema.ResourceData, m interface{}) error {
-	payload, err := json.Marshal(buildZTNAAppRequest(d))
-	if err != nil {
-		return fmt.Errorf("failed to marshal jsc_access_policy payload: %v", err)
-	}
-
-	req, err := http.NewRequest("POST", "https://radar.wandera.com/gate/traffic-routing-service/v1/apps", bytes.NewBuffer(payload))
-	if err != nil {
-		return fmt.Errorf("failed to build jsc_access_policy create request: %v", err)
-	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return fmt.Errorf("jsc_access_policy create request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("failed to create jsc_access_policy: %s", resp.Status)
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
+func resourceZTNAAppCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("failed to read jsc_access_policy create response: %v", err)
+		return diag.FromErr(err)
 	}
-
-	var response struct {
+	response, err := client.Post[struct {
 		ID string `json:"id"`
-	}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return fmt.Errorf("failed to parse jsc_access_policy create response: %v", err)
+	}](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/traffic-routing-service/v1/apps"), buildZTNAAppRequest(d))
+	if err != nil {
+		return client.Diagnostics(err, "failed to create jsc_access_policy", ztnaAppAttributePaths)
 	}
 
 	if response.ID == "" {
-		return fmt.Errorf("jsc_access_policy was created but API returned an empty ID")
+		return diag.FromErr(fmt.Errorf("jsc_access_policy was created but API returned an empty ID"))
 	}
 
 	d.SetId(response.ID)
 	return nil
 }
 
-func resourceZTNAAppRead(d *schema.ResourceData, m interface{}) error {
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/traffic-routing-service/v1/apps/%s", d.Id()), nil)
+func resourceZTNAAppRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("failed to build jsc_access_policy read request: %v", err)
+		return diag.FromErr(err)
 	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return fmt.Errorf("jsc_access_policy read request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusNotFound {
+	response, err := client.Get[ztnaAppResponse](ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/traffic-routing-service/v1/apps/{id}").Param("id", d.Id()))
+	if client.IsNotFound(err) {
 		d.SetId("")
 		return nil
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to read jsc_access_policy: %s", resp.Status)
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read jsc_access_policy read response: %v", err)
-	}
-
-	var response ztnaAppResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return fmt.Errorf("failed to parse jsc_access_policy read response: %v", err)
+		return client.Diagnostics(err, "failed to read jsc_access_policy", nil)
 	}
 
 	d.Set("name", response.Name)
@@ -416,45 +404,27 @@ func resourceZTNAAppRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceZTNAAppUpdate(d *schema.ResourceData, m interface{}) error {
-	payload, err := json.Marshal(buildZTNAAppRequest(d))
+func resourceZTNAAppUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("failed to marshal jsc_access_policy update payload: %v", err)
+		return diag.FromErr(err)
 	}
-
-	req, err := http.NewRequest("PUT", fmt.Sprintf("https://radar.wandera.com/gate/traffic-routing-service/v1/apps/%s", d.Id()), bytes.NewBuffer(payload))
+	err = client.Put(ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/traffic-routing-service/v1/apps/{id}").Param("id", d.Id()), buildZTNAAppRequest(d))
 	if err != nil {
-		return fmt.Errorf("failed to build jsc_access_policy update request: %v", err)
+		return client.Diagnostics(err, "failed to update jsc_access_policy", ztnaAppAttributePaths)
 	}
 
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return fmt.Errorf("jsc_access_policy update request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("failed to update jsc_access_policy: %s (response: %s)", resp.Status, string(body))
-	}
-
-	return resourceZTNAAppRead(d, m)
+	return resourceZTNAAppRead(ctx, d, m)
 }
 
-func resourceZTNAAppDelete(d *schema.ResourceData, m interface{}) error {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("https://radar.wandera.com/gate/traffic-routing-service/v1/apps/%s", d.Id()), nil)
+func resourceZTNAAppDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := auth.ClientFor(d, m)
 	if err != nil {
-		return fmt.Errorf("failed to build jsc_access_policy delete request: %v", err)
+		return diag.FromErr(err)
 	}
-
-	resp, err := auth.MakeRequest(req)
-	if err != nil {
-		return fmt.Errorf("jsc_access_policy delete request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to delete jsc_access_policy: %s", resp.Status)
+	err = client.Delete(ctx, c.MakeRequest, client.URL("https://radar.wandera.com/gate/traffic-routing-service/v1/apps/{id}").Param("id", d.Id()))
+	if err != nil && !client.IsNotFound(err) {
+		return client.Diagnostics(err, "failed to delete jsc_access_policy", nil)
 	}
 
 	d.SetId("")
@@ -0,0 +1,89 @@
+// Copyright 2025, Jamf Software LLC.
+package ztna_app_test
+
+import (
+	"regexp"
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccAccessPolicy_basic(t *testing.T) {
+	s := fakejsc.New(t)
+	config := func(name string, threshold string) string {
+		return acctest.ProviderConfig(s) + `
+resource "jsc_access_policy" "test" {
+  name                         = "` + name + `"
+  hostnames                    = ["intranet.example.com"]
+  routingid                    = "route-1"
+  assignmentgroups             = ["group-1"]
+  securityriskcontrolenabled   = true
+  securityriskcontrolthreshold = "` + threshold + `"
+
+  group_routing_overrides {
+    group_ids    = ["group-1"]
+    routing_type = "DIRECT"
+  }
+}
+`
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		CheckDestroy:             acctest.CheckDestroy(s, fakejsc.Apps, "jsc_access_policy"),
+		Steps: []resource.TestStep{
+			{
+				Config: config("Intranet", "HIGH"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("jsc_access_policy.test", "id"),
+					resource.TestCheckResourceAttr("jsc_access_policy.test", "name", "Intranet"),
+					resource.TestCheckResourceAttr("jsc_access_policy.test", "routingtype", "CUSTOM"),
+					resource.TestCheckResourceAttr("jsc_access_policy.test", "assignmentgroups.0", "group-1"),
+					resource.TestCheckResourceAttr("jsc_access_policy.test", "group_routing_overrides.0.routing_type", "DIRECT"),
+				),
+			},
+			{
+				Config: config("Intranet apps", "MEDIUM"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jsc_access_policy.test", "name", "Intranet apps"),
+					resource.TestCheckResourceAttr("jsc_access_policy.test", "securityriskcontrolthreshold", "MEDIUM"),
+				),
+			},
+			{
+				ResourceName:      "jsc_access_policy.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:             config("Intranet apps", "MEDIUM"),
+				Check:              acctest.Disappears(s, fakejsc.Apps, "jsc_access_policy.test"),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccAccessPolicy_invalidHostname(t *testing.T) {
+	s := fakejsc.New(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(s) + `
+resource "jsc_access_policy" "test" {
+  name      = "Intranet"
+  hostnames = ["intranet.example.com", "not a hostname"]
+  routingid = "route-1"
+}
+`,
+				ExpectError: regexp.MustCompile(`"not a hostname" is not a valid hostname`),
+			},
+		},
+	})
+}
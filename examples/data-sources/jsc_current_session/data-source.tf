@@ -0,0 +1,9 @@
+data "jsc_current_session" "current" {}
+
+# Refuse to plan against anything but the staging tenant
+check "staging_tenant" {
+  assert {
+    condition     = data.jsc_current_session.current.customer_id == "993ae0ee-4bd8-4325-bc5d-1db0ea45b4f6"
+    error_message = "This configuration must only be applied to the staging tenant."
+  }
+}
@@ -0,0 +1,12 @@
+data "jsc_customers" "emea" {
+  name_regex = "^EMEA "
+}
+
+# One module instance per child customer
+module "customer" {
+  source   = "./modules/customer"
+  for_each = { for customer in data.jsc_customers.emea.customers : customer.id => customer if customer.leaf }
+
+  customer_id = each.key
+  name        = each.value.name
+}
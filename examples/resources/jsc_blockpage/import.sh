@@ -0,0 +1,5 @@
+# Block pages are imported by type: block, secureBlock, cap, deviceRisk or deviceManagement
+terraform import jsc_blockpage.myblockpage cap
+
+# Prefix the type with the customer ID to import the block page of a child customer
+terraform import jsc_blockpage.myblockpage 993ae0ee-4bd8-4325-bc5d-1db0ea45b4f6/cap
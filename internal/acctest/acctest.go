@@ -0,0 +1,146 @@
+// Copyright 2025, Jamf Software LLC.
+
+// Package acctest holds the shared setup for acceptance tests run against the fake JSC API
+// in internal/fakejsc.
+package acctest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"jsctfprovider/internal/fakejsc"
+	"jsctfprovider/internal/provider"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// ProtoV6ProviderFactories serves the jsc provider in-process to the Terraform CLI under test,
+// muxed the same way main serves it.
+var ProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"jsc": func() (tfprotov6.ProviderServer, error) {
+		server, err := provider.ProviderServer(context.Background(), "test")
+		if err != nil {
+			return nil, err
+		}
+		return server(), nil
+	},
+}
+
+// ProviderServer returns the provider served the way main serves it, configured to log in to s.
+// It lets tests speak the plugin protocol directly where the Terraform CLI can not be scripted,
+// such as handing a resource state written by an earlier version of the provider.
+func ProviderServer(t *testing.T, s *fakejsc.Server) tfprotov6.ProviderServer {
+	t.Helper()
+	ctx := context.Background()
+	factory, err := provider.ProviderServer(ctx, "test")
+	if err != nil {
+		t.Fatalf("ProviderServer: %v", err)
+	}
+	server := factory()
+
+	// The mux refuses providers whose provider blocks differ
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range schemaResp.Diagnostics {
+		t.Fatalf("GetProviderSchema: %s: %s", d.Summary, d.Detail)
+	}
+
+	values := map[string]tftypes.Value{}
+	for _, attr := range schemaResp.Provider.Block.Attributes {
+		values[attr.Name] = tftypes.NewValue(attr.ValueType(), nil)
+	}
+	values["domain_name"] = tftypes.NewValue(tftypes.String, s.URL)
+	values["pag_domain_name"] = tftypes.NewValue(tftypes.String, s.URL)
+	values["username"] = tftypes.NewValue(tftypes.String, fakejsc.Username)
+	values["password"] = tftypes.NewValue(tftypes.String, fakejsc.Password)
+	values["max_retries"] = tftypes.NewValue(tftypes.Number, 0)
+	config, err := tfprotov6.NewDynamicValue(schemaResp.Provider.ValueType(), tftypes.NewValue(schemaResp.Provider.ValueType(), values))
+	if err != nil {
+		t.Fatal(err)
+	}
+	configureResp, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{Config: &config})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range configureResp.Diagnostics {
+		t.Fatalf("ConfigureProvider: %s: %s", d.Summary, d.Detail)
+	}
+	return server
+}
+
+// PreCheck skips the test unless TF_ACC is set, the same opt-in the test framework uses for
+// acceptance tests. Once opted in, a missing Terraform CLI fails the test: the suite never
+// needs network access, so it does not let the test framework download one.
+func PreCheck(t *testing.T) {
+	t.Helper()
+	if os.Getenv(resource.EnvTfAcc) == "" {
+		t.Skipf("acceptance test skipped, set %s=1 to run it against the fake JSC API", resource.EnvTfAcc)
+	}
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") != "" {
+		return
+	}
+	if _, err := exec.LookPath("terraform"); err != nil {
+		t.Fatalf("%s is set but the terraform CLI is not in PATH and TF_ACC_TERRAFORM_PATH is not set", resource.EnvTfAcc)
+	}
+}
+
+// ProviderConfig returns a provider block that logs in to s with the fake's credentials.
+func ProviderConfig(s *fakejsc.Server) string {
+	return providerConfig(s, false)
+}
+
+// ReadOnlyProviderConfig is ProviderConfig with read_only set, so every mutating request fails.
+func ReadOnlyProviderConfig(s *fakejsc.Server) string {
+	return providerConfig(s, true)
+}
+
+func providerConfig(s *fakejsc.Server, readOnly bool) string {
+	return fmt.Sprintf(`
+provider "jsc" {
+  domain_name       = %[1]q
+  pag_domain_name   = %[1]q
+  username          = %[2]q
+  password          = %[3]q
+  applicationid     = %[4]q
+  applicationsecret = %[5]q
+  max_retries       = 0
+  read_only         = %[6]t
+}
+`, s.URL, fakejsc.Username, fakejsc.Password, fakejsc.ApplicationID, fakejsc.ApplicationSecret, readOnly)
+}
+
+// CheckDestroy fails if any resourceType left in state is still held in the named collection of s.
+func CheckDestroy(s *fakejsc.Server, collection, resourceType string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		for _, rs := range state.RootModule().Resources {
+			if rs.Type == resourceType && s.Has(collection, rs.Primary.ID) {
+				return fmt.Errorf("%s %s still exists", resourceType, rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+// Disappears removes the object behind resourceName from the named collection of s, as if it was
+// deleted in the portal. Steps using it should set ExpectNonEmptyPlan.
+func Disappears(s *fakejsc.Server, collection, resourceName string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("%s not found in state", resourceName)
+		}
+		if !s.Has(collection, rs.Primary.ID) {
+			return fmt.Errorf("%s %s does not exist", resourceName, rs.Primary.ID)
+		}
+		s.Remove(collection, rs.Primary.ID)
+		return nil
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2025, Jamf Software LLC.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// auditRecord is one line of the audit_log_path file. Requests made while creating an object
+// are written once the create has finished, so they carry the ID JSC gave it.
+type auditRecord struct {
+	Timestamp    time.Time `json:"timestamp"`
+	ResourceType string    `json:"resource_type,omitempty"`
+	ResourceID   string    `json:"resource_id,omitempty"`
+	Method       string    `json:"method"`
+	Path         string    `json:"path"`
+	Status       int       `json:"status,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	RequestID    string    `json:"request_id,omitempty"`
+	BodySHA256   string    `json:"body_sha256,omitempty"`
+}
+
+// auditRedactedKeys are the JSON keys, matched case-insensitively by substring, whose values are
+// replaced before a request body is hashed, so the hash can not be used to guess a secret.
+var auditRedactedKeys = []string{"password", "secret", "token", "credential"}
+
+// auditLog appends records to the audit_log_path file. It is shared by a Client and its
+// customer-scoped copies.
+type auditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// openAuditLog opens path for appending, creating it if needed, so a bad path fails when the
+// provider is configured rather than after the first change.
+func openAuditLog(path string) (*auditLog, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &auditLog{file: file}, nil
+}
+
+// write appends record as a single line. Each record is one write call, so concurrent
+// providers appending to the same file do not interleave lines.
+func (l *auditLog) write(record auditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.file.Write(append(line, '\n'))
+	return err
+}
+
+type auditResourceKey struct{}
+
+// auditResource identifies the Terraform resource a request is made for. While the ID is not
+// known yet, records are held in pending until done gives it.
+type auditResource struct {
+	resourceType string
+
+	mu      sync.Mutex
+	id      string
+	done    bool
+	pending []func(id string)
+}
+
+// WithAuditResource returns ctx marked as belonging to the Terraform resource resourceType with
+// the given ID, so mutating requests made with it are attributed to it in audit_log_path. done
+// must be called once the operation has finished with the ID the resource ended up with. When
+// id is empty, as it is on create, requests are only written then, with that ID.
+func WithAuditResource(ctx context.Context, resourceType, id string) (context.Context, func(id string)) {
+	resource := &auditResource{resourceType: resourceType, id: id}
+	return context.WithValue(ctx, auditResourceKey{}, resource), resource.finish
+}
+
+// hold queues write until the ID is known and reports whether it did. It does nothing once
+// the ID was known from the start or the operation has finished.
+func (r *auditResource) hold(write func(id string)) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.id != "" || r.done {
+		return false
+	}
+	r.pending = append(r.pending, write)
+	return true
+}
+
+// finish writes the held records with id.
+func (r *auditResource) finish(id string) {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending, r.done = nil, true
+	r.mu.Unlock()
+	for _, write := range pending {
+		write(id)
+	}
+}
+
+// audit records a mutating request and its outcome in audit_log_path, if configured. A failed
+// write is logged rather than returned, as the request has already been sent.
+func (c *Client) audit(req *http.Request, resp *http.Response, err error) {
+	if c.auditLog == nil {
+		return
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return
+	}
+
+	record := auditRecord{
+		Timestamp:  time.Now().UTC(),
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		BodySHA256: redactedBodyHash(req),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	} else {
+		record.Status = resp.StatusCode
+		record.RequestID = resp.Header.Get("X-Request-Id")
+	}
+
+	resource, _ := req.Context().Value(auditResourceKey{}).(*auditResource)
+	if resource == nil {
+		c.writeAudit(req, record)
+		return
+	}
+	record.ResourceType = resource.resourceType
+	write := func(id string) {
+		record.ResourceID = id
+		c.writeAudit(req, record)
+	}
+	if !resource.hold(write) {
+		write(resource.id)
+	}
+}
+
+// writeAudit appends record to the audit log.
+func (c *Client) writeAudit(req *http.Request, record auditRecord) {
+	if err := c.auditLog.write(record); err != nil {
+		tflog.SubsystemError(c.logContext(req.Context()), subsystemHTTP, "Unable to write to the audit log", map[string]interface{}{
+			"error":  err.Error(),
+			"method": req.Method,
+			"path":   req.URL.Path,
+		})
+	}
+}
+
+// redactedBodyHash returns the hex SHA-256 of the request body with the values of secret fields
+// replaced, or "" when there is no body. JSON bodies are hashed in a canonical form so the
+// same change always has the same hash.
+func redactedBodyHash(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	raw, err := io.ReadAll(body)
+	body.Close()
+	if err != nil || len(raw) == 0 {
+		return ""
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err == nil {
+		if canonical, err := json.Marshal(redact(data)); err == nil {
+			raw = canonical
+		}
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// redact returns v with the values of auditRedactedKeys replaced at any depth.
+func redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isRedactedKey(key) {
+				v[key] = "REDACTED"
+			} else {
+				v[key] = redact(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redact(value)
+		}
+	}
+	return v
+}
+
+func isRedactedKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, redacted := range auditRedactedKeys {
+		if strings.Contains(key, redacted) {
+			return true
+		}
+	}
+	return false
+}
@@ -3,43 +3,200 @@ package auth
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
-	"net"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/sync/singleflight"
+	"jsctfprovider/internal/client"
 )
 
-var xsrfToken string
-var sessionCookie string
-var pagjwt string
-var holdCustomerid string
-var providerDomainName string
+// Config holds the provider settings used to build a Client.
+type Config struct {
+	DomainName        string
+	Username          string
+	Password          string
+	Customerid        string
+	Applicationid     string
+	Applicationsecret string
+	PAGDomainName     string
+	TotpSecret        string
+	BackupCode        string
+	Retry             RetryPolicy
+	Transport         TransportConfig
+	SessionCacheDir   string // optional, caches the encrypted session between runs
+	ReadOnly          bool   // refuse every request that could change the tenant
+	AuditLogPath      string // optional, JSONL file every mutating request is appended to
+
+	// Client-side limits shared by every request of the provider, zero for unlimited
+	RequestsPerSecond     float64
+	MaxConcurrentRequests int
+
+	// Jamf ID (Auth0) authorization used when local login fails. Discovered from
+	// login-methods when empty.
+	JamfIDRegistration string
+	JamfIDConnection   string
+}
+
+// session holds the login state shared by a Client and every customer-scoped
+// copy of it returned by ForCustomer. Requests run in parallel, so every field
+// is read and written under mu. A login replaces the RADAR or PAG credentials as
+// a whole and bumps their generation, which tells a refused request whether the
+// session it sent has been replaced since.
+type session struct {
+	mu sync.Mutex
+
+	xsrfToken       string
+	sessionCookie   string
+	radarGeneration uint64
+	radarMethod     AuthMethod
+	pagjwt          string
+	pagjwtExpiry    time.Time
+	pagGeneration   uint64
+
+	backupCodeUsed bool // backup codes are single use, so only the first login may send it
+
+	jamfIDAuthorization string // Jamf ID authorization URL advertised by login-methods, if any
+
+	customerid string // provider default customer resolved at login
+
+	visibleCustomerids []string // leaf customers visible to the admin, loaded on first use
+
+	cacheMu  sync.Mutex       // serialises session cache reads and writes
+	cacheKey *sessionCacheKey // derived on first use of session_cache_dir, guarded by cacheMu
+
+	logins singleflight.Group // re-authentication in progress, keyed by API
+}
+
+// radarSession returns the current RADAR session and its generation.
+func (s *session) radarSession() (sessionCookie, xsrfToken string, generation uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessionCookie, s.xsrfToken, s.radarGeneration
+}
+
+// setRadarSession replaces the RADAR session after a login with method.
+func (s *session) setRadarSession(sessionCookie, xsrfToken string, method AuthMethod) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessionCookie = sessionCookie
+	s.xsrfToken = xsrfToken
+	s.radarMethod = method
+	s.radarGeneration++
+}
+
+// pagSession returns the current PAG token, when it expires and its generation.
+func (s *session) pagSession() (jwt string, expiry time.Time, generation uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pagjwt, s.pagjwtExpiry, s.pagGeneration
+}
+
+// setPAGSession replaces the PAG token after a login.
+func (s *session) setPAGSession(jwt string, expiry time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pagjwt = jwt
+	s.pagjwtExpiry = expiry
+	s.pagGeneration++
+}
+
+// defaultCustomerid returns the provider default customer, empty before the first login.
+func (s *session) defaultCustomerid() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.customerid
+}
+
+func (s *session) setDefaultCustomerid(customerid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.customerid = customerid
+}
+
+// takeBackupCode reports whether the backup code is still unused and marks it used.
+func (s *session) takeBackupCode() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.backupCodeUsed {
+		return false
+	}
+	s.backupCodeUsed = true
+	return true
+}
 
-var holdUsername string
-var holdPassword string
+// Client holds the credentials, session and HTTP client for a single provider
+// configuration. It is returned from providerConfigure and handed to every
+// resource and data source as meta, so aliased providers never share a session.
+type Client struct {
+	config  Config
+	session *session
 
-func AuthenticatePAG(Applicationid string, Applicationsecret string) error {
+	ctx context.Context // carries the Terraform logger for requests built without a context
+
+	holdCustomerid string // customer set by ForCustomer, empty for the provider default
+
+	httpClient *http.Client
+	limiter    *rateLimiter
+	auditLog   *auditLog // nil unless audit_log_path is set
+}
+
+// NewClient returns an unauthenticated Client for the given configuration. ctx is only used for
+// logging and may outlive the call. It fails when the transport settings, such as the proxy URL
+// or CA bundle, are invalid, or the audit log can not be opened.
+func NewClient(ctx context.Context, config Config) (*Client, error) {
+	if config.PAGDomainName == "" {
+		config.PAGDomainName = DefaultPAGDomainName
+	}
+	ctx = withLogSubsystems(context.WithoutCancel(ctx))
+	httpClient, err := newHTTPClient(ctx, config.Transport)
+	if err != nil {
+		return nil, err
+	}
+	var audit *auditLog
+	if config.AuditLogPath != "" {
+		if audit, err = openAuditLog(config.AuditLogPath); err != nil {
+			return nil, fmt.Errorf("unable to open audit_log_path: %w", err)
+		}
+	}
+	return &Client{
+		config:     config,
+		session:    &session{},
+		ctx:        ctx,
+		httpClient: httpClient,
+		limiter:    newRateLimiter(config.RequestsPerSecond, config.MaxConcurrentRequests),
+		auditLog:   audit,
+	}, nil
+}
+
+// AuthenticatePAG logs in to the PAG API with the application ID and secret. Cancelling ctx
+// abandons the login.
+func (c *Client) AuthenticatePAG(ctx context.Context) error {
+	tflog.SubsystemDebug(c.logContext(ctx), subsystemAuth, "Logging in to the PAG API", map[string]interface{}{
+		"host": c.config.PAGDomainName,
+	})
 
 	// Struct to hold the response data
 	type ApiResponse struct {
 		Token string `json:"token"`
 	}
 
-	const apidomain = "api.wandera.com"
-
 	// Create the Basic Authentication string
-	auth := Applicationid + ":" + Applicationsecret
+	auth := c.config.Applicationid + ":" + c.config.Applicationsecret
 	encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
 
 	// Create the request with the Basic Authentication header
-	req, err := http.NewRequest("POST", fmt.Sprintf("https://%s/v1/login", apidomain), nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", domainURL(c.config.PAGDomainName, "/v1/login"), nil)
 	if err != nil {
 		return err
 	}
@@ -48,8 +205,7 @@ func AuthenticatePAG(Applicationid string, Applicationsecret string) error {
 	req.Header.Add("Authorization", "Basic "+encodedAuth)
 
 	// Make the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.send(req, 1)
 	if err != nil {
 		return err
 	}
@@ -71,21 +227,41 @@ func AuthenticatePAG(Applicationid string, Applicationsecret string) error {
 		return fmt.Errorf("failed to parse response: %v", err)
 	}
 
-	pagjwt = apiResponse.Token
+	// Remember when the token runs out so MakePAGRequest can log in again before it does
+	expiry, err := jwtExpiry(apiResponse.Token)
+	ctx = c.logContext(ctx)
+	if err != nil {
+		tflog.SubsystemWarn(ctx, subsystemAuth, "Unable to read PAG token expiry, it will be refreshed on 401", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	c.session.setPAGSession(apiResponse.Token, expiry)
+	tflog.SubsystemDebug(ctx, subsystemAuth, "Logged in to the PAG API", map[string]interface{}{
+		"expiry": expiry,
+	})
+	c.saveSessionCache()
 
 	return nil
 }
 
-func StoreRadarAuthVars(DomainName string) error {
-	providerDomainName = DomainName
+// AuthenticateRadarAPI logs in to the RADAR API with the username and password, falling back to
+// Jamf ID when local login is refused. Cancelling ctx abandons the login.
+func (c *Client) AuthenticateRadarAPI(ctx context.Context) error {
+	DomainName := c.config.DomainName
+	Username := c.config.Username
+	Password := c.config.Password
 
-	return nil
-}
-
-func AuthenticateRadarAPI(DomainName string, Username string, Password string, Customerid string) error {
+	tflog.SubsystemDebug(c.logContext(ctx), subsystemAuth, "Logging in to the RADAR API", map[string]interface{}{
+		"host":     DomainName,
+		"username": Username,
+	})
 
 	// Make a GET request to obtain cookies
-	resp, err := http.Get(fmt.Sprintf("https://%s/auth/v1/login-methods?email=%s", DomainName, Username))
+	req, err := http.NewRequestWithContext(ctx, "GET", domainURL(DomainName, "/auth/v1/login-methods?email="+url.QueryEscape(Username)), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.send(req, 1)
 	if err != nil {
 		return err
 	}
@@ -96,24 +272,42 @@ func AuthenticateRadarAPI(DomainName string, Username string, Password string, C
 		return fmt.Errorf("failed to get cookies: %s", resp.Status)
 	}
 
+	// Remember where Jamf ID logins start for this user, in case local login is refused
+	if body, err := ioutil.ReadAll(resp.Body); err == nil {
+		authorization := findJamfIDAuthorization(body)
+		c.session.mu.Lock()
+		c.session.jamfIDAuthorization = authorization
+		c.session.mu.Unlock()
+	}
+
 	// Extract cookies from the response
 	cookies := resp.Cookies()
 
 	// Extract the value of the first cookie
-	//var xsrfToken string
+	var xsrfToken string
 	if len(cookies) > 0 {
 		xsrfToken = cookies[0].Value
-		//fmt.Errorf(xsrfToken)
+	}
+
+	// Generate a fresh MFA code on every login, including re-authentication from MakeRequest
+	totp := ""
+	if c.config.TotpSecret != "" {
+		totp, err = generateTOTP(c.config.TotpSecret, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+	backupCode := ""
+	if c.config.BackupCode != "" && c.session.takeBackupCode() {
+		backupCode = c.config.BackupCode
 	}
 
 	// Construct the authentication request body
-	holdUsername = Username //if we need to call Auth function again later
-	holdPassword = Password
 	authData := map[string]string{
 		"username":   Username, //hardcoded in PoC but can come from template or ENV
 		"password":   Password,
-		"totp":       "",
-		"backupCode": "",
+		"totp":       totp,
+		"backupCode": backupCode,
 	}
 	payload, err := json.Marshal(authData)
 	if err != nil {
@@ -121,9 +315,7 @@ func AuthenticateRadarAPI(DomainName string, Username string, Password string, C
 	}
 
 	// Make a POST request to authenticate with cookies
-	client := &http.Client{}
-	url := fmt.Sprintf("https://%s/auth/v1/credentials", DomainName)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+	req, err = http.NewRequestWithContext(ctx, "POST", domainURL(DomainName, "/auth/v1/credentials"), bytes.NewBuffer(payload))
 	if err != nil {
 		return err
 	}
@@ -134,7 +326,7 @@ func AuthenticateRadarAPI(DomainName string, Username string, Password string, C
 
 	req.Header.Set("X-Xsrf-Token", xsrfToken)
 
-	resp, err = client.Do(req)
+	resp, err = c.send(req, 1)
 	if err != nil {
 		return err
 	}
@@ -143,25 +335,21 @@ func AuthenticateRadarAPI(DomainName string, Username string, Password string, C
 	// Check the response status code
 	if resp.StatusCode != http.StatusOK {
 		// Try Jamf ID Authentication as fallback
-		log.Printf("[INFO] Local auth failed (%s), attempting Jamf ID authentication...\n", resp.Status)
-		jamfSession, jamfXsrf, err := AuthenticateViaJamfID(DomainName, Username, Password)
+		tflog.SubsystemInfo(c.logContext(ctx), subsystemAuth, "Local login failed, attempting Jamf ID login", map[string]interface{}{
+			"status": resp.StatusCode,
+		})
+		jamfSession, jamfXsrf, err := c.AuthenticateViaJamfID(ctx, Username, Password)
 		if err != nil {
 			return fmt.Errorf("authentication failed: %s. Local auth failed and Jamf ID auth failed: %v", resp.Status, err)
 		}
-		// Success! Set the package-level variables
-		sessionCookie = jamfSession
+		// Success! Store the session on the client
 		if jamfXsrf != "" {
 			xsrfToken = jamfXsrf
 		}
-		// We can return early or fall through to let findCustomerid run?
+		c.session.setRadarSession(jamfSession, xsrfToken, AuthMethodJamfID)
 		// Ensure we don't try to parse the body of the FAILED local auth response below.
-
-		if Customerid == "empty" {
-			//Customerid not provided so attempt to find from endpoint
-			findCustomerid(DomainName)
-		} else {
-			holdCustomerid = Customerid
-		}
+		c.resolveCustomerid(ctx)
+		c.saveSessionCache()
 		return nil
 	}
 
@@ -181,52 +369,67 @@ func AuthenticateRadarAPI(DomainName string, Username string, Password string, C
 	// Store the authentication token
 	authcookies := resp.Cookies()
 
+	var sessionCookie string
 	for _, cookie := range authcookies {
 		if cookie.Name == "SESSION" {
 			sessionCookie = cookie.Value
 		}
 	}
+	c.session.setRadarSession(sessionCookie, xsrfToken, AuthMethodLocal)
+	tflog.SubsystemDebug(c.logContext(ctx), subsystemAuth, "Logged in to the RADAR API with a local account")
 
-	if Customerid == "empty" {
-		//Customerid not provided so attempt to find from endpiint
-		findCustomerid(DomainName)
-	} else {
-		holdCustomerid = Customerid
-	}
+	c.resolveCustomerid(ctx)
+	c.saveSessionCache()
 	return nil
 }
 
-func findCustomerid(DomainName string) {
-	client := &http.Client{}
-	url := (fmt.Sprintf("https://%s/auth/v1/me", DomainName))
+// resolveCustomerid sets the provider default customer after a login. It is looked up once
+// when customer_id is not configured, as it does not change when the session is renewed.
+// Customer-scoped copies keep their own customer, whichever client logged in.
+func (c *Client) resolveCustomerid(ctx context.Context) {
+	if c.config.Customerid != "empty" {
+		c.session.setDefaultCustomerid(c.config.Customerid)
+		return
+	}
+	if c.session.defaultCustomerid() == "" {
+		//Customerid not provided so attempt to find from endpoint
+		c.findCustomerid(ctx)
+	}
+}
+
+func (c *Client) findCustomerid(ctx context.Context) {
+	logCtx := c.logContext(ctx)
+	DomainName := c.config.DomainName
+	url := domainURL(DomainName, "/auth/v1/me")
 	//req, err := http.NewRequest("GET", fmt.Sprintf("https://radar.wandera.com/gate/content-block-service/v1/customers/{customerid}/categories"), nil)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		log.Println("[ERROR] Error creating request:", err)
+		tflog.SubsystemError(logCtx, subsystemAuth, "Unable to build the customer lookup request", map[string]interface{}{"error": err.Error()})
 		return
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
+	sessionCookie, xsrfToken, _ := c.session.radarSession()
 	req.Header.Set("X-Xsrf-Token", xsrfToken)
 	req.AddCookie(&http.Cookie{Name: "SESSION", Value: sessionCookie, Path: "/", SameSite: http.SameSiteLaxMode, Secure: true, HttpOnly: true})
 	req.AddCookie(&http.Cookie{Name: "XSRF-TOKEN", Value: xsrfToken})
-	resp, err := client.Do(req)
+	resp, err := c.send(req, 1)
 	if err != nil {
-		log.Println("[ERROR] Error sending request:", err)
+		tflog.SubsystemError(logCtx, subsystemAuth, "Customer lookup failed", map[string]interface{}{"error": err.Error()})
 		return
 	}
 	defer resp.Body.Close()
 	// Check the response status code
 	if resp.StatusCode != http.StatusOK {
-		//fmt.Println("customerid checking failed: %s", resp.Status)
+		tflog.SubsystemError(logCtx, subsystemAuth, "Customer lookup failed", map[string]interface{}{"status": resp.StatusCode})
 		return
 	}
 
 	// Read the response body
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		log.Println("[ERROR] Error reading response body:", err)
+		tflog.SubsystemError(logCtx, subsystemAuth, "Unable to read the customer lookup response", map[string]interface{}{"error": err.Error()})
 		return
 	}
 
@@ -235,190 +438,180 @@ func findCustomerid(DomainName string) {
 	var result map[string]interface{}
 	jsonerr := json.Unmarshal(body, &result)
 	if jsonerr != nil {
-		log.Println("[ERROR] Error unmarshalling JSON:", jsonerr)
+		tflog.SubsystemError(logCtx, subsystemAuth, "Unable to parse the customer lookup response", map[string]interface{}{"error": jsonerr.Error()})
 		return
 	}
 	//check if login user is parent or customer type
+	var customerid string
 	if result["admin"].(map[string]interface{})["entityType"].(string) == "CUSTOMER" {
 		// Extract entityId
-		entityId := result["admin"].(map[string]interface{})["entityId"].(string)
-		holdCustomerid = entityId
+		customerid = result["admin"].(map[string]interface{})["entityId"].(string)
 	} else {
-		urlCheckParent := (fmt.Sprintf("https://%s/gate/user-service/customer/v2/customers/visible-for-admin", DomainName))
-		req, err := http.NewRequest("GET", urlCheckParent, nil)
+		customerIds, err := c.visibleLeafCustomerids(ctx)
 		if err != nil {
-			log.Println("[ERROR] Error creating request:", err)
+			tflog.SubsystemError(logCtx, subsystemAuth, "Unable to list visible customers", map[string]interface{}{"error": err.Error()})
 			return
 		}
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Accept", "application/json")
-		req.Header.Set("X-Xsrf-Token", xsrfToken)
-		req.AddCookie(&http.Cookie{Name: "SESSION", Value: sessionCookie, Path: "/", SameSite: http.SameSiteLaxMode, Secure: true, HttpOnly: true})
-		req.AddCookie(&http.Cookie{Name: "XSRF-TOKEN", Value: xsrfToken})
-		resp, err := client.Do(req)
-		if err != nil {
-			log.Println("[ERROR] Error sending request:", err)
-			return
-		}
-		defer resp.Body.Close()
-		// Check the response status code
-		if resp.StatusCode != http.StatusOK {
-			//fmt.Println("customerid checking failed: %s", resp.Status)
-			return
-		}
-		// Read the response body
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			log.Println("[ERROR] Error reading response body:", err)
+		if len(customerIds) == 0 {
+			tflog.SubsystemError(logCtx, subsystemAuth, "No leaf customers are visible to this admin")
 			return
 		}
+		customerid = customerIds[0] // default for a parent - other children are reached via customer_id on each resource
+	}
+	c.session.setDefaultCustomerid(customerid)
+	tflog.SubsystemDebug(logCtx, subsystemAuth, "Resolved customer", map[string]interface{}{
+		"customer_id": customerid,
+	})
 
-		// Parse the response JSON to get the customerid
-		// Unmarshal JSON into an interface slice
-		var data []map[string]json.RawMessage
-		errmarshall := json.Unmarshal([]byte(body), &data)
-		if errmarshall != nil {
-			log.Println("[ERROR] Error unmarshalling JSON:", errmarshall)
-			return
-		}
+}
 
-		// Filter and collect customerId where leaf is true
-		var customerIds []string
-		for _, customer := range data {
-			var leaf bool
-			err := json.Unmarshal(customer["leaf"], &leaf)
-			if err != nil {
-				log.Println("[ERROR] Error unmarshalling leaf:", err)
-				continue
-			}
+// ErrReadOnly is returned by MakeRequest and MakePAGRequest for a mutating request when the
+// provider is configured with read_only.
+var ErrReadOnly = errors.New("the provider is configured with read_only")
 
-			if leaf {
-				var customerId string
-				err := json.Unmarshal(customer["customerId"], &customerId)
-				if err != nil {
-					log.Println("[ERROR] Error unmarshalling customerId:", err)
-					continue
-				}
-				customerIds = append(customerIds, customerId)
-			}
-		}
-		holdCustomerid = customerIds[0] // can a parent have more than 1 customer - well they can define it manually in the provider then
+// checkReadOnly refuses req before it is sent when the client is read only and req could
+// change the tenant. Logins do not go through here, so a read only provider can still log in.
+func (c *Client) checkReadOnly(req *http.Request) error {
+	if !c.config.ReadOnly {
+		return nil
 	}
-
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+	return fmt.Errorf("%w, refusing to send %s %s. Unset read_only (JSC_READ_ONLY) to make changes", ErrReadOnly, req.Method, req.URL.Path)
 }
-func MakeRequest(req *http.Request) (*http.Response, error) {
-	if sessionCookie == "" {
+
+func (c *Client) MakeRequest(req *http.Request) (*http.Response, error) {
+	if err := c.checkReadOnly(req); err != nil {
+		return nil, err
+	}
+	if sessionCookie, _, _ := c.session.radarSession(); sessionCookie == "" {
 		return nil, fmt.Errorf("error RADAR API not authenticated")
 	}
-	client := &http.Client{Timeout: 121 * time.Second}
-
-	maxRetries := 2
-	retryDelay := 2 * time.Second
 
 	// Preserve the request body for retries (body is consumed after each attempt)
-	var bodyBytes []byte
-	if req.Body != nil {
-		var err error
-		bodyBytes, err = ioutil.ReadAll(req.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read request body: %w", err)
-		}
-		req.Body.Close()
-		req.Body = ioutil.NopCloser(bytes.NewReader(bodyBytes))
-		req.GetBody = func() (io.ReadCloser, error) {
-			return ioutil.NopCloser(bytes.NewReader(bodyBytes)), nil
-		}
-	}
-
-	log.Println("[INFO] Building the client")
-	log.Println("[INFO] incoming url is " + req.URL.Path)
-
-	// Properly append customerId to existing query parameters
-	if req.URL.RawQuery != "" {
-		req.URL.RawQuery += "&customerId=" + holdCustomerid
-	} else {
-		req.URL.RawQuery = "customerId=" + holdCustomerid
+	if err := bufferBody(req); err != nil {
+		return nil, err
 	}
 
-	log.Println("new url query is " + req.URL.RawQuery)
-	req.URL.Path = strings.Replace(req.URL.Path, "{customerid}", holdCustomerid, -1)
-	req.Host = providerDomainName     //swap out domain if something specific is provided
-	req.URL.Host = providerDomainName //in both the path AND the host field
-	log.Println("new raw url is " + req.URL.Path)
-	log.Println("raw host is " + string(req.Host))
+	fillCustomer(req.URL, c.customerid())
+	rewriteHost(req, c.config.DomainName) //swap out domain if something specific is provided
 
 	// Send the request using the client
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("X-Xsrf-Token", xsrfToken)
 
-	var resp2 *http.Response
-	var err error
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		log.Printf("[INFO] Attempt %d/%d...\n", attempt, maxRetries)
-		// Reset the request body for retries (body is consumed after first attempt)
-		if req.GetBody != nil {
-			req.Body, _ = req.GetBody()
-		}
+	var sent uint64 // generation of the session sent by the last attempt
+	resp, err := c.doWithRetry(req, func(req *http.Request) error {
+		// Replace rather than add, the session may have changed since the last attempt
+		sessionCookie, xsrfToken, generation := c.session.radarSession()
+		sent = generation
+		req.Header.Del("Cookie")
+		req.Header.Set("X-Xsrf-Token", xsrfToken)
 		req.AddCookie(&http.Cookie{Name: "SESSION", Value: sessionCookie, Path: "/", SameSite: http.SameSiteLaxMode, Secure: true, HttpOnly: true})
 		req.AddCookie(&http.Cookie{Name: "XSRF-TOKEN", Value: xsrfToken})
-		resp2, err = client.Do(req)
-		if err != nil {
-			AuthenticateRadarAPI(providerDomainName, holdUsername, holdPassword, holdCustomerid) // try and get another cookie etc
-			// Check if the error is a timeout error by checking for net.Error and the Timeout() method
-			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-				log.Printf("[ERROR] Timeout occurred: %v, retrying in %v...\n", netErr, retryDelay)
-				time.Sleep(retryDelay) // Wait before retrying
-				continue
-			}
-			log.Printf("[ERROR] Request failed with error: %v\n", err)
-			time.Sleep(retryDelay) // Wait before retrying
-			continue
-			//return nil, err // do not return error but try again for non-timeout but errors
-		}
-		// Check HTTP response status
-		if resp2.StatusCode >= 400 {
-			AuthenticateRadarAPI(providerDomainName, holdUsername, holdPassword, holdCustomerid) // try and get another cookie etc
-			log.Printf("[ERROR] Request failed with response code: %v\n", resp2.StatusCode)
-			time.Sleep(retryDelay) // Wait before retrying
-			continue
-		}
-		break
+		return nil
+	}, func() error {
+		return c.relogin(req.Context(), "radar", func() bool {
+			_, _, generation := c.session.radarSession()
+			return generation == sent
+		}, c.AuthenticateRadarAPI)
+	})
+	c.audit(req, resp, err)
+	return resp, err
+}
 
-	}
-	if err != nil {
+func (c *Client) MakePAGRequest(req *http.Request) (*http.Response, error) {
+	if err := c.checkReadOnly(req); err != nil {
 		return nil, err
 	}
+	if strings.Contains(req.URL.Path, client.CustomerPlaceholder) || strings.Contains(req.URL.RawQuery, client.CustomerPlaceholder) {
+		return nil, fmt.Errorf("PAG endpoints are not customer scoped, %s %s must use client.NoCustomer", req.Method, req.URL.Path)
+	}
+	if jwt, _, _ := c.session.pagSession(); jwt == "" {
+		return nil, fmt.Errorf("error PAG JWT API not authenticated")
+	}
 
-	if resp2 == nil {
-		// If we exhausted all retries and still have no response, return an error
-		return nil, errors.New("failed to get a response after all retries")
+	// Preserve the request body for retries (body is consumed after each attempt)
+	if err := bufferBody(req); err != nil {
+		return nil, err
 	}
-	//defer resp2.Body.Close()
 
-	return resp2, nil
+	rewriteHost(req, c.config.PAGDomainName) //PAG endpoints are built against the default gateway
+	// Send the request using the client
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
 
+	var sent uint64 // generation of the token sent by the last attempt
+	resp, err := c.doWithRetry(req, func(req *http.Request) error {
+		// Log in again before the token runs out rather than waiting for a 401 part way through an apply
+		if c.pagTokenExpiring() {
+			tflog.SubsystemInfo(c.logContext(req.Context()), subsystemAuth, "PAG token expired or about to expire, logging in again")
+			if err := c.relogin(req.Context(), "pag", c.pagTokenExpiring, c.AuthenticatePAG); err != nil {
+				return err
+			}
+		}
+		// Add Bearer Token for authentication
+		jwt, _, generation := c.session.pagSession()
+		sent = generation
+		req.Header.Set("Authorization", "Bearer "+jwt)
+		return nil
+	}, func() error {
+		return c.relogin(req.Context(), "pag", func() bool {
+			_, _, generation := c.session.pagSession()
+			return generation == sent
+		}, c.AuthenticatePAG)
+	})
+	c.audit(req, resp, err)
+	return resp, err
 }
 
-func MakePAGRequest(req *http.Request) (*http.Response, error) {
-	if pagjwt == "" {
-		return nil, fmt.Errorf("error PAG JWT API not authenticated")
+// customerPlaceholderEscaped is how the placeholder appears in an escaped path.
+var customerPlaceholderEscaped = url.PathEscape(client.CustomerPlaceholder)
+
+// fillCustomer replaces the customer placeholder in the path and query of u with customerid.
+// Nothing is added when the placeholder is missing, as the endpoint is not customer specific.
+func fillCustomer(u *url.URL, customerid string) {
+	u.Path = strings.ReplaceAll(u.Path, client.CustomerPlaceholder, customerid)
+	u.RawPath = strings.ReplaceAll(u.RawPath, customerPlaceholderEscaped, url.PathEscape(customerid))
+	u.RawQuery = strings.ReplaceAll(u.RawQuery, client.CustomerPlaceholder, url.QueryEscape(customerid))
+}
+
+// relogin runs login for api unless another request already logged in again. Requests that
+// need a new session at the same time share a single login and all wait for its result, and
+// stale reports whether the session is still the generation that needed replacing once any
+// login in progress has finished, so a request refused on an old session never logs in twice.
+// The login is made with the ctx of the request that started it, and a request whose ctx is
+// cancelled stops waiting for it.
+func (c *Client) relogin(ctx context.Context, api string, stale func() bool, login func(context.Context) error) error {
+	result := c.session.logins.DoChan(api, func() (interface{}, error) {
+		if !stale() {
+			return nil, nil
+		}
+		return nil, login(ctx)
+	})
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case r := <-result:
+		return r.Err
 	}
-	client := &http.Client{}
-	log.Println("[INFO] Building the PAG client")
-	log.Println("[INFO] incoming url is " + req.URL.Path)
-	// Send the request using the client
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-	// Add Bearer Token for authentication
-	req.Header.Set("Authorization", "Bearer "+pagjwt)
+}
 
-	resp2, err := client.Do(req)
+// bufferBody reads the request body into memory and sets GetBody so the request can be resent.
+func bufferBody(req *http.Request) error {
+	if req.Body == nil {
+		return nil
+	}
+	bodyBytes, err := ioutil.ReadAll(req.Body)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to read request body: %w", err)
 	}
-	//defer resp2.Body.Close()
-
-	return resp2, nil
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(bodyBytes))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(bodyBytes)), nil
+	}
+	return nil
 }
-
@@ -0,0 +1,858 @@
+// Copyright 2025, Jamf Software LLC.
+package auth_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// newClient returns a client logged in to both APIs of s.
+func newClient(t *testing.T, s *fakejsc.Server) *auth.Client {
+	t.Helper()
+	c, err := auth.NewClient(context.Background(), auth.Config{
+		DomainName:        s.URL,
+		PAGDomainName:     s.URL,
+		Username:          fakejsc.Username,
+		Password:          fakejsc.Password,
+		Customerid:        "empty",
+		Applicationid:     fakejsc.ApplicationID,
+		Applicationsecret: fakejsc.ApplicationSecret,
+		Retry:             auth.RetryPolicy{MaxRetries: 2, MinWait: time.Millisecond, MaxWait: 10 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := c.AuthenticateRadarAPI(context.Background()); err != nil {
+		t.Fatalf("AuthenticateRadarAPI: %v", err)
+	}
+	if err := c.AuthenticatePAG(context.Background()); err != nil {
+		t.Fatalf("AuthenticatePAG: %v", err)
+	}
+	return c
+}
+
+// do sends a request through send and returns the status code and body.
+func do(t *testing.T, send func(*http.Request) (*http.Response, error), method, url string, body string) (int, string) {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := send(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(respBody)
+}
+
+// count returns how many requests s received for the given "METHOD path" prefix.
+func count(s *fakejsc.Server, prefix string) int {
+	n := 0
+	for _, r := range s.Requests() {
+		if strings.HasPrefix(r, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestMakeRequestRewritesHostAndScopesCustomer(t *testing.T) {
+	s := fakejsc.New(t)
+	c := newClient(t, s)
+
+	// The customer is only filled in where the endpoint puts the placeholder, never added on top
+	tests := map[string]struct {
+		url  string
+		want string
+	}{
+		"path": {
+			url:  "https://radar.wandera.com/gate/content-block-service/v1/customers/{customerid}/categories?sort=name",
+			want: "GET /gate/content-block-service/v1/customers/" + fakejsc.CustomerID + "/categories?sort=name",
+		},
+		"query": {
+			url:  "https://radar.wandera.com/gate/identity-service/v1/connections?sort=name&customerId={customerid}",
+			want: "GET /gate/identity-service/v1/connections?sort=name&customerId=" + fakejsc.CustomerID,
+		},
+		"none": {
+			url:  "https://radar.wandera.com/gate/traffic-routing-service/v1/app-templates",
+			want: "GET /gate/traffic-routing-service/v1/app-templates",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			status, _ := do(t, c.MakeRequest, "GET", tt.url, "")
+			if status != http.StatusOK {
+				t.Fatalf("status = %d, want 200", status)
+			}
+			if !slices.Contains(s.Requests(), tt.want) {
+				t.Errorf("request %q not received, got %q", tt.want, s.Requests())
+			}
+		})
+	}
+
+	req, err := http.NewRequest("GET", "https://api.wandera.com/ztna/v1/apps?customerId={customerid}", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.MakePAGRequest(req); err == nil {
+		t.Error("MakePAGRequest sent a customer scoped request, want an error")
+	}
+}
+
+func TestMakeRequestReauthenticatesExpiredSession(t *testing.T) {
+	s := fakejsc.New(t)
+	c := newClient(t, s)
+	s.ExpireSessions()
+
+	status, _ := do(t, c.MakeRequest, "GET", "https://radar.wandera.com/gate/identity-service/v1/connections", "")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if n := count(s, "POST /auth/v1/credentials"); n != 2 {
+		t.Errorf("logged in %d times, want 2", n)
+	}
+}
+
+func TestMakeRequestWithRetriesOff(t *testing.T) {
+	s := fakejsc.New(t)
+	c, err := auth.NewClient(context.Background(), auth.Config{
+		DomainName: s.URL,
+		Username:   fakejsc.Username,
+		Password:   fakejsc.Password,
+		Customerid: "empty",
+		Retry:      auth.RetryPolicy{MaxRetries: 0},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := c.AuthenticateRadarAPI(context.Background()); err != nil {
+		t.Fatalf("AuthenticateRadarAPI: %v", err)
+	}
+
+	// max_retries = 0 is not replaced with the default policy
+	s.Fail("/gate/identity-service/v1/connections", http.StatusServiceUnavailable, 1)
+	status, _ := do(t, c.MakeRequest, "GET", "https://radar.wandera.com/gate/identity-service/v1/connections", "")
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", status)
+	}
+	if n := count(s, "GET /gate/identity-service/v1/connections"); n != 1 {
+		t.Errorf("sent %d requests, want 1", n)
+	}
+
+	// Logging in again is not a retry, so an expired session is still renewed
+	s.ExpireSessions()
+	status, _ = do(t, c.MakeRequest, "GET", "https://radar.wandera.com/gate/identity-service/v1/connections", "")
+	if status != http.StatusOK {
+		t.Errorf("status after the session expired = %d, want 200", status)
+	}
+	if n := count(s, "POST /auth/v1/credentials"); n != 2 {
+		t.Errorf("logged in %d times, want 2", n)
+	}
+}
+
+func TestMakeRequestRetriesIdempotentRequests(t *testing.T) {
+	s := fakejsc.New(t)
+	c := newClient(t, s)
+	s.Fail("/gate/identity-service/v1/connections", http.StatusServiceUnavailable, 2)
+
+	status, _ := do(t, c.MakeRequest, "GET", "https://radar.wandera.com/gate/identity-service/v1/connections", "")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if n := count(s, "GET /gate/identity-service/v1/connections"); n != 3 {
+		t.Errorf("sent %d requests, want 3", n)
+	}
+}
+
+func TestMakeRequestDoesNotRetryPost(t *testing.T) {
+	s := fakejsc.New(t)
+	c := newClient(t, s)
+	s.Fail("/gate/identity-service/v1/connections", http.StatusInternalServerError, 1)
+
+	status, _ := do(t, c.MakeRequest, "POST", "https://radar.wandera.com/gate/identity-service/v1/connections", `{"name":"Okta","type":"OKTA"}`)
+	if status != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", status)
+	}
+	if n := count(s, "POST /gate/identity-service/v1/connections"); n != 1 {
+		t.Errorf("sent %d requests, want 1", n)
+	}
+	if s.Has(fakejsc.Connections, "connection-1") {
+		t.Error("connection was created by a retried POST")
+	}
+}
+
+func TestMakeRequestStopsRetryingWhenCancelled(t *testing.T) {
+	s := fakejsc.New(t)
+	c, err := auth.NewClient(context.Background(), auth.Config{
+		DomainName: s.URL,
+		Username:   fakejsc.Username,
+		Password:   fakejsc.Password,
+		Customerid: "empty",
+		Retry:      auth.RetryPolicy{MaxRetries: 2, MinWait: time.Minute, MaxWait: time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := c.AuthenticateRadarAPI(context.Background()); err != nil {
+		t.Fatalf("AuthenticateRadarAPI: %v", err)
+	}
+	s.Fail("/gate/identity-service/v1/connections", http.StatusServiceUnavailable, 3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://radar.wandera.com/gate/identity-service/v1/connections", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err = c.MakeRequest(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("cancelled request took %v, want it to stop waiting for the retry", elapsed)
+	}
+}
+
+func TestMakeRequestCancelsReauthentication(t *testing.T) {
+	s := fakejsc.New(t)
+	c := newClient(t, s)
+	s.ExpireSessions()
+	release := s.Hold("/auth/v1/credentials")
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://radar.wandera.com/gate/identity-service/v1/connections", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err = c.MakeRequest(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("cancelled request took %v, want it to stop waiting for the login", elapsed)
+	}
+	if n := count(s, "POST /auth/v1/credentials"); n != 2 {
+		t.Errorf("credentials requests = %d, want 2", n)
+	}
+}
+
+func TestMakePAGRequestReauthenticatesExpiredToken(t *testing.T) {
+	s := fakejsc.New(t)
+	c := newClient(t, s)
+	s.ExpireSessions()
+
+	status, body := do(t, c.MakePAGRequest, "POST", "https://api.wandera.com/ztna/v1/apps", `{"name":"Intranet"}`)
+	if status != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", status, body)
+	}
+	if n := count(s, "POST /v1/login"); n != 2 {
+		t.Errorf("logged in %d times, want 2", n)
+	}
+	if !s.Has(fakejsc.PAGApps, "pag-app-1") {
+		t.Error("app was not created")
+	}
+}
+
+func TestMakePAGRequestRenewsExpiringToken(t *testing.T) {
+	s := fakejsc.New(t)
+	// Inside the refresh window from the start, so every request logs in again first
+	s.SetPAGTokenLifetime(30 * time.Second)
+	c := newClient(t, s)
+
+	status, body := do(t, c.MakePAGRequest, "GET", "https://api.wandera.com/ztna/v1/apps", "")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", status, body)
+	}
+	var pag []string
+	for _, r := range s.Requests() {
+		if strings.HasPrefix(r, "POST /v1/login") || strings.HasPrefix(r, "GET /ztna/") {
+			pag = append(pag, r)
+		}
+	}
+	// A single GET means the request was not refused with a 401 and sent again
+	want := []string{"POST /v1/login", "POST /v1/login", "GET /ztna/v1/apps"}
+	if !slices.Equal(pag, want) {
+		t.Errorf("PAG requests = %q, want %q", pag, want)
+	}
+}
+
+func TestReadOnlyRefusesMutatingRequests(t *testing.T) {
+	s := fakejsc.New(t)
+	c, err := auth.NewClient(context.Background(), auth.Config{
+		DomainName:        s.URL,
+		PAGDomainName:     s.URL,
+		Username:          fakejsc.Username,
+		Password:          fakejsc.Password,
+		Customerid:        "empty",
+		Applicationid:     fakejsc.ApplicationID,
+		Applicationsecret: fakejsc.ApplicationSecret,
+		ReadOnly:          true,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := c.AuthenticateRadarAPI(context.Background()); err != nil {
+		t.Fatalf("AuthenticateRadarAPI: %v", err)
+	}
+	if err := c.AuthenticatePAG(context.Background()); err != nil {
+		t.Fatalf("AuthenticatePAG: %v", err)
+	}
+
+	if status, _ := do(t, c.MakeRequest, "GET", "https://radar.wandera.com/gate/identity-service/v1/connections", ""); status != http.StatusOK {
+		t.Errorf("GET status = %d, want 200", status)
+	}
+
+	tests := []struct {
+		send   func(*http.Request) (*http.Response, error)
+		method string
+		url    string
+	}{
+		{c.MakeRequest, "POST", "https://radar.wandera.com/gate/identity-service/v1/connections"},
+		{c.MakeRequest, "PUT", "https://radar.wandera.com/gate/identity-service/v1/connections/connection-1"},
+		{c.MakeRequest, "DELETE", "https://radar.wandera.com/gate/identity-service/v1/connections/connection-1"},
+		{c.MakePAGRequest, "PATCH", "https://api.wandera.com/ztna/v1/apps/pag-app-1"},
+		{c.MakePAGRequest, "POST", "https://api.wandera.com/ztna/v1/apps"},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, tt.url, strings.NewReader(`{"name":"Okta"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tt.send(req); !errors.Is(err, auth.ErrReadOnly) {
+			t.Errorf("%s %s: err = %v, want ErrReadOnly", tt.method, tt.url, err)
+		}
+	}
+
+	for _, r := range s.Requests() {
+		if !strings.HasPrefix(r, "GET ") && !strings.HasPrefix(r, "POST /auth/") && !strings.HasPrefix(r, "POST /v1/login") {
+			t.Errorf("read only client sent %q", r)
+		}
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	s := fakejsc.New(t)
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	c, err := auth.NewClient(context.Background(), auth.Config{
+		DomainName:        s.URL,
+		PAGDomainName:     s.URL,
+		Username:          fakejsc.Username,
+		Password:          fakejsc.Password,
+		Customerid:        "empty",
+		Applicationid:     fakejsc.ApplicationID,
+		Applicationsecret: fakejsc.ApplicationSecret,
+		AuditLogPath:      path,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := c.AuthenticateRadarAPI(context.Background()); err != nil {
+		t.Fatalf("AuthenticateRadarAPI: %v", err)
+	}
+
+	send := func(ctx context.Context, method, url, body string) {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := c.MakeRequest(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, url, err)
+		}
+		resp.Body.Close()
+	}
+	// A create is written once it has finished, with the ID of the new object
+	ctx, done := auth.WithAuditResource(context.Background(), "jsc_oktaidp", "")
+	send(ctx, "POST", "https://radar.wandera.com/gate/identity-service/v1/connections", `{"name":"Okta","clientSecret":"first"}`)
+	send(ctx, "GET", "https://radar.wandera.com/gate/identity-service/v1/connections", "")
+	if raw, _ := os.ReadFile(path); len(raw) != 0 {
+		t.Errorf("create was written before it finished: %s", raw)
+	}
+	done("connection-1")
+	ctx, done = auth.WithAuditResource(context.Background(), "jsc_oktaidp", "connection-1")
+	send(ctx, "DELETE", "https://radar.wandera.com/gate/identity-service/v1/connections/connection-1", `{"clientSecret":"second", "name":"Okta"}`)
+	done("")
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "first") || strings.Contains(string(raw), "second") {
+		t.Errorf("audit log contains a secret: %s", raw)
+	}
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("audit log has %d lines, want 2: %s", len(lines), raw)
+	}
+
+	var records []map[string]interface{}
+	for _, line := range lines {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("audit line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	want := []map[string]interface{}{
+		{"resource_type": "jsc_oktaidp", "resource_id": "connection-1", "method": "POST", "path": "/gate/identity-service/v1/connections", "status": float64(201)},
+		{"resource_type": "jsc_oktaidp", "resource_id": "connection-1", "method": "DELETE", "path": "/gate/identity-service/v1/connections/connection-1", "status": float64(204)},
+	}
+	for i, record := range records {
+		for key, value := range want[i] {
+			if record[key] != value {
+				t.Errorf("record %d %s = %v, want %v", i, key, record[key], value)
+			}
+		}
+		for _, key := range []string{"timestamp", "request_id", "body_sha256"} {
+			if record[key] == nil || record[key] == "" {
+				t.Errorf("record %d is missing %s: %v", i, key, record)
+			}
+		}
+	}
+	// Both bodies only differ in the secret and key order
+	if records[0]["body_sha256"] != records[1]["body_sha256"] {
+		t.Errorf("body hashes differ after redaction: %v and %v", records[0]["body_sha256"], records[1]["body_sha256"])
+	}
+
+	if _, err := auth.NewClient(context.Background(), auth.Config{AuditLogPath: t.TempDir()}); err == nil {
+		t.Error("NewClient accepted a directory as audit_log_path")
+	}
+}
+
+func TestForCustomer(t *testing.T) {
+	s := fakejsc.New(t)
+	s.SetCustomers(
+		fakejsc.Customer{ID: "parent", Name: "Parent", Leaf: false},
+		fakejsc.Customer{ID: "child-1", Name: "Child 1", Leaf: true, ParentID: "parent"},
+		fakejsc.Customer{ID: "child-2", Name: "Child 2", Leaf: true, ParentID: "parent"},
+	)
+	c := newClient(t, s)
+
+	// A parent admin defaults to the first visible leaf customer
+	do(t, c.MakeRequest, "GET", "https://radar.wandera.com/gate/identity-service/v1/connections?customerId={customerid}", "")
+	if !slices.Contains(s.Requests(), "GET /gate/identity-service/v1/connections?customerId=child-1") {
+		t.Errorf("request was not scoped to child-1, got %q", s.Requests())
+	}
+
+	scoped, err := c.ForCustomer("child-2")
+	if err != nil {
+		t.Fatalf("ForCustomer(child-2): %v", err)
+	}
+	do(t, scoped.MakeRequest, "GET", "https://radar.wandera.com/gate/identity-service/v1/connections?customerId={customerid}", "")
+	if !slices.Contains(s.Requests(), "GET /gate/identity-service/v1/connections?customerId=child-2") {
+		t.Errorf("request was not scoped to child-2, got %q", s.Requests())
+	}
+
+	if _, err := c.ForCustomer("parent"); err == nil {
+		t.Error("ForCustomer(parent) succeeded, want an error for a non-leaf customer")
+	}
+	if _, err := c.ForCustomer("someone-else"); err == nil {
+		t.Error("ForCustomer(someone-else) succeeded, want an error for an invisible customer")
+	}
+}
+
+func TestForCustomerKeepsTenantsApart(t *testing.T) {
+	s := fakejsc.New(t)
+	s.SetCustomers(
+		fakejsc.Customer{ID: "parent", Name: "Parent", Leaf: false},
+		fakejsc.Customer{ID: "child-1", Name: "Child 1", Leaf: true, ParentID: "parent"},
+		fakejsc.Customer{ID: "child-2", Name: "Child 2", Leaf: true, ParentID: "parent"},
+	)
+	c := newClient(t, s)
+	child1, err := c.ForCustomer("child-1")
+	if err != nil {
+		t.Fatalf("ForCustomer(child-1): %v", err)
+	}
+	child2, err := c.ForCustomer("child-2")
+	if err != nil {
+		t.Fatalf("ForCustomer(child-2): %v", err)
+	}
+
+	status, body := do(t, child1.MakeRequest, "POST", "https://radar.wandera.com/gate/traffic-routing-service/v1/apps?customerId={customerid}", `{"name":"app"}`)
+	if status != http.StatusCreated {
+		t.Fatalf("create under child-1: status %d: %s", status, body)
+	}
+	var app struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(body), &app); err != nil {
+		t.Fatal(err)
+	}
+
+	url := "https://radar.wandera.com/gate/traffic-routing-service/v1/apps/" + app.ID + "?customerId={customerid}"
+	if status, body := do(t, child1.MakeRequest, "GET", url, ""); status != http.StatusOK {
+		t.Errorf("read under child-1: status %d: %s", status, body)
+	}
+	if status, _ := do(t, child2.MakeRequest, "GET", url, ""); status != http.StatusNotFound {
+		t.Errorf("read under child-2: status %d, want %d", status, http.StatusNotFound)
+	}
+	if _, body := do(t, child2.MakeRequest, "GET", "https://radar.wandera.com/gate/traffic-routing-service/v1/apps?customerId={customerid}", ""); body != "[]\n" {
+		t.Errorf("list under child-2 = %s, want no apps", body)
+	}
+}
+
+func TestImportCustomerState(t *testing.T) {
+	resourceSchema := map[string]*schema.Schema{"customer_id": auth.CustomerIDSchema()}
+	tests := map[string]struct {
+		importID     string
+		wantID       string
+		wantCustomer string
+	}{
+		"plain ID":            {importID: "admin-1", wantID: "admin-1"},
+		"customer and ID":     {importID: "child-2/admin-1", wantID: "admin-1", wantCustomer: "child-2"},
+		"ID with a separator": {importID: "child-2/a/b", wantID: "a/b", wantCustomer: "child-2"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{})
+			d.SetId(tt.importID)
+			imported, err := auth.ImportCustomerState(context.Background(), d, nil)
+			if err != nil {
+				t.Fatalf("ImportCustomerState: %v", err)
+			}
+			if got := imported[0].Id(); got != tt.wantID {
+				t.Errorf("ID = %q, want %q", got, tt.wantID)
+			}
+			if got := imported[0].Get("customer_id"); got != tt.wantCustomer {
+				t.Errorf("customer_id = %q, want %q", got, tt.wantCustomer)
+			}
+		})
+	}
+
+	for _, importID := range []string{"/admin-1", "child-2/"} {
+		d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{})
+		d.SetId(importID)
+		if _, err := auth.ImportCustomerState(context.Background(), d, nil); err == nil {
+			t.Errorf("ImportCustomerState(%q) succeeded, want an error", importID)
+		}
+	}
+}
+
+func TestSessionCache(t *testing.T) {
+	s := fakejsc.New(t)
+	dir := t.TempDir()
+	config := auth.Config{
+		DomainName:        s.URL,
+		PAGDomainName:     s.URL,
+		Username:          fakejsc.Username,
+		Password:          fakejsc.Password,
+		Customerid:        "empty",
+		Applicationid:     fakejsc.ApplicationID,
+		Applicationsecret: fakejsc.ApplicationSecret,
+		SessionCacheDir:   dir,
+	}
+	restore := func(config auth.Config) (*auth.Client, bool, bool) {
+		t.Helper()
+		c, err := auth.NewClient(context.Background(), config)
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		return c, c.RestoreRadarSession(context.Background()), c.RestorePAGSession()
+	}
+
+	first, radar, pag := restore(config)
+	if radar || pag {
+		t.Fatal("restored a session from an empty cache")
+	}
+	if err := first.AuthenticateRadarAPI(context.Background()); err != nil {
+		t.Fatalf("AuthenticateRadarAPI: %v", err)
+	}
+	if err := first.AuthenticatePAG(context.Background()); err != nil {
+		t.Fatalf("AuthenticatePAG: %v", err)
+	}
+
+	second, radar, pag := restore(config)
+	if !radar || !pag {
+		t.Fatalf("cached session not restored: radar %v, pag %v", radar, pag)
+	}
+	if n := count(s, "POST /auth/v1/credentials"); n != 1 {
+		t.Errorf("logged in %d times, want 1", n)
+	}
+	status, _ := do(t, second.MakeRequest, "GET", "https://radar.wandera.com/gate/identity-service/v1/connections?customerId={customerid}", "")
+	if status != http.StatusOK {
+		t.Errorf("request with restored session: status = %d, want 200", status)
+	}
+	if !slices.Contains(s.Requests(), "GET /gate/identity-service/v1/connections?customerId="+fakejsc.CustomerID) {
+		t.Errorf("restored session did not keep the customer, got %q", s.Requests())
+	}
+	status, _ = do(t, second.MakePAGRequest, "GET", "https://api.wandera.com/ztna/v1/apps", "")
+	if status != http.StatusOK {
+		t.Errorf("PAG request with restored token: status = %d, want 200", status)
+	}
+
+	// A cache written with other credentials can not be read
+	other := config
+	other.Password = "other-password"
+	if _, radar, _ := restore(other); radar {
+		t.Error("restored a session encrypted with another password")
+	}
+
+	s.ExpireSessions()
+	if _, radar, _ := restore(config); radar {
+		t.Error("restored an expired RADAR session")
+	}
+}
+
+// jamfIDClient returns an unauthenticated client for the Jamf ID user of s.
+func jamfIDClient(t *testing.T, s *fakejsc.Server, config auth.Config) *auth.Client {
+	t.Helper()
+	config.DomainName = s.URL
+	config.Username = fakejsc.JamfIDUsername
+	config.Customerid = "empty"
+	if config.Password == "" {
+		config.Password = fakejsc.Password
+	}
+	c, err := auth.NewClient(context.Background(), config)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c
+}
+
+func TestJamfIDLogin(t *testing.T) {
+	s := fakejsc.New(t)
+	c := jamfIDClient(t, s, auth.Config{})
+	if err := c.AuthenticateRadarAPI(context.Background()); err != nil {
+		t.Fatalf("AuthenticateRadarAPI: %v", err)
+	}
+	if !slices.Contains(s.Requests(), "GET "+fakejsc.JamfIDAuthorizationPath) {
+		t.Errorf("advertised authorization was not used, got %q", s.Requests())
+	}
+	status, _ := do(t, c.MakeRequest, "GET", "https://radar.wandera.com/gate/identity-service/v1/connections", "")
+	if status != http.StatusOK {
+		t.Errorf("request after Jamf ID login: status = %d, want 200", status)
+	}
+	if method := c.AuthMethod(); method != auth.AuthMethodJamfID {
+		t.Errorf("AuthMethod = %q, want %q", method, auth.AuthMethodJamfID)
+	}
+}
+
+func TestJamfIDLoginAnswersOTPChallenge(t *testing.T) {
+	s := fakejsc.New(t)
+	s.RequireJamfIDChallenge("/u/mfa-otp-challenge")
+
+	err := jamfIDClient(t, s, auth.Config{}).AuthenticateRadarAPI(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "set totp_secret") {
+		t.Errorf("login without totp_secret: err = %v, want a hint to set totp_secret", err)
+	}
+
+	s.RequireTOTP("JBSWY3DPEHPK3PXP")
+	if err := jamfIDClient(t, s, auth.Config{TotpSecret: "JBSWY3DPEHPK3PXP"}).AuthenticateRadarAPI(context.Background()); err != nil {
+		t.Errorf("login with totp_secret: %v", err)
+	}
+	if err := jamfIDClient(t, s, auth.Config{TotpSecret: "GEZDGNBVGY3TQOJQ"}).AuthenticateRadarAPI(context.Background()); err == nil {
+		t.Error("login with the wrong totp_secret succeeded")
+	}
+}
+
+// localMFAClient returns a client for the local admin of s with the MFA settings of config.
+func localMFAClient(t *testing.T, s *fakejsc.Server, config auth.Config) *auth.Client {
+	t.Helper()
+	config.DomainName = s.URL
+	config.Username = fakejsc.Username
+	config.Password = fakejsc.Password
+	config.Customerid = "empty"
+	c, err := auth.NewClient(context.Background(), config)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c
+}
+
+func TestLocalLoginSendsTOTP(t *testing.T) {
+	s := fakejsc.New(t)
+	s.RequireTOTP("JBSWY3DPEHPK3PXP")
+
+	if err := localMFAClient(t, s, auth.Config{}).AuthenticateRadarAPI(context.Background()); err == nil {
+		t.Error("login without totp_secret succeeded")
+	}
+	if err := localMFAClient(t, s, auth.Config{TotpSecret: "GEZDGNBVGY3TQOJQ"}).AuthenticateRadarAPI(context.Background()); err == nil {
+		t.Error("login with the wrong totp_secret succeeded")
+	}
+
+	c := localMFAClient(t, s, auth.Config{TotpSecret: "JBSWY3DPEHPK3PXP"})
+	if err := c.AuthenticateRadarAPI(context.Background()); err != nil {
+		t.Fatalf("login with totp_secret: %v", err)
+	}
+	// Re-authentication needs a code as well
+	s.ExpireSessions()
+	status, _ := do(t, c.MakeRequest, "GET", "https://radar.wandera.com/gate/identity-service/v1/connections?customerId={customerid}", "")
+	if status != http.StatusOK {
+		t.Errorf("request after the session expired: status = %d, want 200", status)
+	}
+	if method := c.AuthMethod(); method != auth.AuthMethodLocal {
+		t.Errorf("AuthMethod = %q, want %q", method, auth.AuthMethodLocal)
+	}
+}
+
+func TestLocalLoginUsesBackupCodeOnce(t *testing.T) {
+	s := fakejsc.New(t)
+	s.RequireTOTP("JBSWY3DPEHPK3PXP")
+
+	c := localMFAClient(t, s, auth.Config{BackupCode: fakejsc.BackupCode})
+	if err := c.AuthenticateRadarAPI(context.Background()); err != nil {
+		t.Fatalf("login with backup_code: %v", err)
+	}
+	// The code is spent, so the client can not log in again on its own
+	if err := c.AuthenticateRadarAPI(context.Background()); err == nil {
+		t.Error("second login with the same backup_code succeeded")
+	}
+}
+
+func TestJamfIDLoginErrors(t *testing.T) {
+	tests := map[string]struct {
+		config    auth.Config
+		challenge string
+		want      string
+	}{
+		"wrong password": {
+			config: auth.Config{Password: "wrong"},
+			want:   "rejected the password: Wrong email or password",
+		},
+		"unsupported MFA": {
+			challenge: "/u/mfa-sms-challenge",
+			want:      "MFA challenge the provider can not answer (/u/mfa-sms-challenge)",
+		},
+		"consent": {
+			challenge: "/u/consent",
+			want:      "Jamf ID is asking to authorize JSC (/u/consent)",
+		},
+		"wrong region": {
+			config: auth.Config{JamfIDRegistration: "jamf-auth0-us"},
+			want:   "Check jamf_id_registration",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := fakejsc.New(t)
+			if tt.challenge != "" {
+				s.RequireJamfIDChallenge(tt.challenge)
+			}
+			err := jamfIDClient(t, s, tt.config).AuthenticateRadarAPI(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeRequestReauthenticatesOnceForConcurrentRequests(t *testing.T) {
+	s := fakejsc.New(t)
+	c := newClient(t, s)
+	s.ExpireSessions()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest("GET", "https://radar.wandera.com/gate/identity-service/v1/connections", nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp, err := c.MakeRequest(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("status = %d, want 200", resp.StatusCode)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := count(s, "POST /auth/v1/credentials"); n != 2 {
+		t.Errorf("logged in %d times, want 2", n)
+	}
+}
+
+func TestForCustomerKeepsCustomerWhenReauthenticating(t *testing.T) {
+	s := fakejsc.New(t)
+	s.SetCustomers(
+		fakejsc.Customer{ID: "parent", Name: "Parent", Leaf: false},
+		fakejsc.Customer{ID: "child-1", Name: "Child 1", Leaf: true, ParentID: "parent"},
+		fakejsc.Customer{ID: "child-2", Name: "Child 2", Leaf: true, ParentID: "parent"},
+	)
+	c := newClient(t, s)
+	scoped, err := c.ForCustomer("child-2")
+	if err != nil {
+		t.Fatalf("ForCustomer(child-2): %v", err)
+	}
+	s.ExpireSessions()
+
+	// Whichever client is refused first logs in for both
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		client := c
+		if i%2 == 1 {
+			client = scoped
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest("GET", "https://radar.wandera.com/gate/identity-service/v1/connections?customerId={customerid}", nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp, err := client.MakeRequest(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("status = %d, want 200", resp.StatusCode)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := count(s, "POST /auth/v1/credentials"); n != 2 {
+		t.Errorf("logged in %d times, want 2", n)
+	}
+	for _, customer := range []string{"child-1", "child-2"} {
+		if n := count(s, "GET /gate/identity-service/v1/connections?customerId="+customer); n < 5 {
+			t.Errorf("%d requests scoped to %s, want at least 5", n, customer)
+		}
+	}
+
+	// A copy that logs in by itself must not fall back to the provider default either
+	s.ExpireSessions()
+	do(t, scoped.MakeRequest, "GET", "https://radar.wandera.com/gate/identity-service/v1/connections?after=expiry&customerId={customerid}", "")
+	if !slices.Contains(s.Requests(), "GET /gate/identity-service/v1/connections?after=expiry&customerId=child-2") {
+		t.Errorf("request after re-authentication was not scoped to child-2, got %q", s.Requests())
+	}
+	if _, err := scoped.ForCustomer("child-1"); err != nil {
+		t.Errorf("ForCustomer(child-1) from a scoped copy: %v", err)
+	}
+}
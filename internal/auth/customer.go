@@ -0,0 +1,168 @@
+// Copyright 2025, Jamf Software LLC.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	fwschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// CustomerIDSchema returns the optional per-resource customer_id attribute that lets a
+// parent (MSP) admin target a child customer other than the provider default.
+func CustomerIDSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    true,
+		Description: "The optional customer ID this object belongs to. Defaults to the provider customer. Must be visible to the authenticated admin.",
+	}
+}
+
+// CustomerIDAttribute is CustomerIDSchema for resources served by terraform-plugin-framework.
+func CustomerIDAttribute() fwschema.StringAttribute {
+	return fwschema.StringAttribute{
+		Optional:      true,
+		Description:   CustomerIDSchema().Description,
+		PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+	}
+}
+
+// DataSourceCustomerIDSchema is the data source variant of CustomerIDSchema.
+func DataSourceCustomerIDSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "The optional customer ID to read from. Defaults to the provider customer. Must be visible to the authenticated admin.",
+	}
+}
+
+// ImportCustomerState imports resources that have a customer_id. An import ID of the form
+// <customer_id>/<id> imports an object of that customer; a plain ID imports from the provider
+// customer.
+func ImportCustomerState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if customerid, id, ok := strings.Cut(d.Id(), "/"); ok {
+		if customerid == "" || id == "" {
+			return nil, fmt.Errorf("unexpected import ID %q, expected <customer_id>/<id> or <id>", d.Id())
+		}
+		if err := d.Set("customer_id", customerid); err != nil {
+			return nil, err
+		}
+		d.SetId(id)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// ClientFor returns the provider client scoped to the customer_id set on d, if any.
+func ClientFor(d *schema.ResourceData, m interface{}) (*Client, error) {
+	client := m.(*Client)
+	return client.ForCustomer(d.Get("customer_id").(string))
+}
+
+// customerid returns the customer requests are sent for: the one set by ForCustomer, or the
+// provider default resolved at login.
+func (c *Client) customerid() string {
+	if c.holdCustomerid != "" {
+		return c.holdCustomerid
+	}
+	return c.session.defaultCustomerid()
+}
+
+// ForCustomer returns a copy of the client that sends requests for customerid while sharing
+// the provider session. An empty customerid returns the client unchanged.
+func (c *Client) ForCustomer(customerid string) (*Client, error) {
+	if customerid == "" || customerid == c.customerid() {
+		return c, nil
+	}
+
+	customerIds, err := c.visibleLeafCustomerids(c.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to validate customer_id %s: %v", customerid, err)
+	}
+	for _, id := range customerIds {
+		if id == customerid {
+			scoped := *c
+			scoped.holdCustomerid = customerid
+			return &scoped, nil
+		}
+	}
+
+	return nil, fmt.Errorf("customer_id %s is not visible to the authenticated admin", customerid)
+}
+
+// visibleLeafCustomerids lists the leaf customers visible to the logged in admin. The result
+// is cached on the session as it does not change during a run.
+func (c *Client) visibleLeafCustomerids(ctx context.Context) ([]string, error) {
+	c.session.mu.Lock()
+	visible := c.session.visibleCustomerids
+	c.session.mu.Unlock()
+	if visible != nil {
+		return visible, nil
+	}
+
+	urlCheckParent := domainURL(c.config.DomainName, "/gate/user-service/customer/v2/customers/visible-for-admin")
+	req, err := http.NewRequestWithContext(ctx, "GET", urlCheckParent, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	sessionCookie, xsrfToken, _ := c.session.radarSession()
+	req.Header.Set("X-Xsrf-Token", xsrfToken)
+	req.AddCookie(&http.Cookie{Name: "SESSION", Value: sessionCookie, Path: "/", SameSite: http.SameSiteLaxMode, Secure: true, HttpOnly: true})
+	req.AddCookie(&http.Cookie{Name: "XSRF-TOKEN", Value: xsrfToken})
+	resp, err := c.send(req, 1)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	// Check the response status code
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list visible customers: %s", resp.Status)
+	}
+	// Read the response body
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal JSON into an interface slice
+	var data []map[string]json.RawMessage
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+
+	// Filter and collect customerId where leaf is true
+	customerIds := []string{}
+	for _, customer := range data {
+		var leaf bool
+		err := json.Unmarshal(customer["leaf"], &leaf)
+		if err != nil {
+			tflog.SubsystemError(c.logContext(ctx), subsystemAuth, "Unable to parse leaf of visible customer", map[string]interface{}{"error": err.Error()})
+			continue
+		}
+
+		if leaf {
+			var customerId string
+			err := json.Unmarshal(customer["customerId"], &customerId)
+			if err != nil {
+				tflog.SubsystemError(c.logContext(ctx), subsystemAuth, "Unable to parse customerId of visible customer", map[string]interface{}{"error": err.Error()})
+				continue
+			}
+			customerIds = append(customerIds, customerId)
+		}
+	}
+
+	c.session.mu.Lock()
+	c.session.visibleCustomerids = customerIds
+	c.session.mu.Unlock()
+	return customerIds, nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2025, Jamf Software LLC.
+package auth
+
+import (
+	"net/http"
+	"strings"
+)
+
+// DefaultPAGDomainName is the Risk API gateway used when pag_domain_name is not set.
+const DefaultPAGDomainName = "api.wandera.com"
+
+// splitDomain splits a configured domain into scheme and host. Domains without a scheme
+// default to https, so "api.wandera.com" and "https://api.wandera.com" are equivalent and
+// "http://127.0.0.1:8080" can be used to point the provider at a local stand-in server.
+func splitDomain(domain string) (string, string) {
+	domain = strings.TrimRight(domain, "/")
+	if scheme, host, ok := strings.Cut(domain, "://"); ok {
+		return scheme, host
+	}
+	return "https", domain
+}
+
+// domainURL builds an absolute URL for path on the configured domain.
+func domainURL(domain string, path string) string {
+	scheme, host := splitDomain(domain)
+	return scheme + "://" + host + path
+}
+
+// rewriteHost points req at the configured domain, keeping its path and query. Endpoints
+// build their URLs against the default hosts, so every request goes through here.
+func rewriteHost(req *http.Request, domain string) {
+	scheme, host := splitDomain(domain)
+	req.URL.Scheme = scheme
+	req.URL.Host = host //in both the path AND the host field
+	req.Host = host
+}
@@ -0,0 +1,37 @@
+// Copyright 2025, Jamf Software LLC.
+package auth
+
+// AuthMethod is how a Client logged in.
+type AuthMethod string
+
+const (
+	AuthMethodLocal  AuthMethod = "local"   // RADAR username and password
+	AuthMethodJamfID AuthMethod = "jamf_id" // RADAR through Jamf ID, after the local login was refused
+	AuthMethodPAG    AuthMethod = "pag"     // PAG application credentials only
+)
+
+// AuthMethod reports how the client logged in, or "" before any login. The RADAR login wins
+// when both APIs are configured, as it is the one that scopes requests to a customer.
+func (c *Client) AuthMethod() AuthMethod {
+	c.session.mu.Lock()
+	defer c.session.mu.Unlock()
+	switch {
+	case c.session.sessionCookie != "":
+		return c.session.radarMethod
+	case c.session.pagjwt != "":
+		return AuthMethodPAG
+	}
+	return ""
+}
+
+// CustomerID returns the customer RADAR requests are sent for: the one the client was scoped
+// to with ForCustomer, or the provider default resolved at login.
+func (c *Client) CustomerID() string {
+	return c.customerid()
+}
+
+// Domain returns the host of the RADAR API the client talks to.
+func (c *Client) Domain() string {
+	_, host := splitDomain(c.config.DomainName)
+	return host
+}
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -8,105 +10,195 @@ import (
 	"net/http/cookiejar"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"golang.org/x/net/html"
 )
 
+// Jamf ID authorization used when it is neither configured nor advertised by login-methods.
+const (
+	defaultJamfIDRegistration = "jamf-auth0-us"
+	defaultJamfIDConnection   = "jamf-id-db"
+)
+
+// jamfIDMaxSteps bounds the number of login pages walked before giving up, so an unexpected
+// redirect loop fails instead of spinning.
+const jamfIDMaxSteps = 8
+
+// jamfIDAuthorizationPath returns the path that starts the Jamf ID login. A configured
+// registration wins, then the one advertised by login-methods, then the US default.
+func (c *Client) jamfIDAuthorizationPath() string {
+	registration := c.config.JamfIDRegistration
+	connection := c.config.JamfIDConnection
+
+	c.session.mu.Lock()
+	authorization := c.session.jamfIDAuthorization
+	c.session.mu.Unlock()
+	if registration == "" && authorization != "" {
+		if u, err := url.Parse(authorization); err == nil {
+			if connection != "" {
+				query := u.Query()
+				query.Set("connection", connection)
+				u.RawQuery = query.Encode()
+			}
+			return u.RequestURI()
+		}
+	}
+
+	if registration == "" {
+		registration = defaultJamfIDRegistration
+	}
+	if connection == "" {
+		connection = defaultJamfIDConnection
+	}
+	return "/oauth2/authorization/" + url.PathEscape(registration) + "?connection=" + url.QueryEscape(connection)
+}
+
+// findJamfIDAuthorization returns the first OAuth authorization URL in a login-methods
+// response, wherever it appears, or "" when there is none.
+func findJamfIDAuthorization(body []byte) string {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return ""
+	}
+	var find func(interface{}) string
+	find = func(v interface{}) string {
+		switch v := v.(type) {
+		case string:
+			if strings.Contains(v, "/oauth2/authorization/") {
+				return v
+			}
+		case []interface{}:
+			for _, item := range v {
+				if found := find(item); found != "" {
+					return found
+				}
+			}
+		case map[string]interface{}:
+			for _, item := range v {
+				if found := find(item); found != "" {
+					return found
+				}
+			}
+		}
+		return ""
+	}
+	return find(data)
+}
+
 // AuthenticateViaJamfID authenticates using the Jamf ID (Auth0) flow.
-// It emulates a browser to follow redirects, parse forms, and submit credentials.
+// It emulates a browser to follow redirects, parse forms, and submit credentials, answering
+// authenticator app challenges with totp_secret. Any other page, such as an SMS challenge or a
+// consent screen, is reported as an error naming the page.
 // Returns sessionCookie and xsrfToken.
-func AuthenticateViaJamfID(domain, username, password string) (string, string, error) {
+func (c *Client) AuthenticateViaJamfID(ctx context.Context, username, password string) (string, string, error) {
+	domain := c.config.DomainName
+	logCtx := c.logContext(ctx)
 	jar, err := cookiejar.New(nil)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to create cookie jar: %w", err)
 	}
 
+	// Share the provider transport (proxy, CA bundle, User-Agent) but keep a separate cookie jar
 	client := &http.Client{
-		Jar: jar,
+		Jar:       jar,
+		Transport: c.httpClient.Transport,
+		Timeout:   c.httpClient.Timeout,
 	}
 
 	// 1. Initial Request to kick off OAuth flow
-	initialURL := fmt.Sprintf("https://%s/oauth2/authorization/jamf-auth0-us?connection=jamf-id-db", domain)
-	resp, err := client.Get(initialURL)
-	if err != nil {
-		return "", "", fmt.Errorf("initial request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", "", fmt.Errorf("initial request returned status: %s", resp.Status)
-	}
-
-	// 2. We should be at /u/login/identifier via redirects.
-	currentURL := resp.Request.URL.String()
-	if !strings.Contains(currentURL, "/u/login/identifier") {
-		return "", "", fmt.Errorf("unexpected URL after initial redirect: %s", currentURL)
-	}
-
-	formData, err := extractFormData(resp.Body)
+	authorizationPath := c.jamfIDAuthorizationPath()
+	tflog.SubsystemDebug(logCtx, subsystemAuth, "Starting Jamf ID login", map[string]interface{}{
+		"authorization": strings.SplitN(authorizationPath, "?", 2)[0],
+	})
+	req, err := http.NewRequestWithContext(ctx, "GET", domainURL(domain, authorizationPath), nil)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to parse identifier form: %w", err)
+		return "", "", err
 	}
-
-	// Fill in username
-	formData.Set("username", username)
-	formData.Set("action", "default")
-
-	// 3. Post to /u/login/identifier
-	resp, err = client.PostForm(currentURL, formData)
+	resp, err := client.Do(req)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to submit identifier: %w", err)
+		return "", "", fmt.Errorf("initial request failed: %w", err)
 	}
-	defer resp.Body.Close()
-
 	if resp.StatusCode != http.StatusOK {
-		return "", "", fmt.Errorf("identifier submission returned status: %s", resp.Status)
+		resp.Body.Close()
+		return "", "", fmt.Errorf("Jamf ID authorization %s returned status: %s. Check jamf_id_registration and jamf_id_connection match the region of the tenant", authorizationPath, resp.Status)
 	}
 
-	// 4. We should be at /u/login/password
-	currentURL = resp.Request.URL.String()
-	if strings.Contains(currentURL, "/u/login/identifier") {
-		return "", "", errors.New("stuck at identifier step, possibly invalid username")
-	}
-	// It's possible we are already authenticated or redirected elsewhere?
-	// But assuming strict flow:
-	if !strings.Contains(currentURL, "/u/login/password") {
-		// It might be that we skipped password if session was active?
-		// But we have empty cookie jar.
-		return "", "", fmt.Errorf("unexpected URL after identifier submission: %s", currentURL)
-	}
-
-	formData, err = extractFormData(resp.Body)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to parse password form: %w", err)
-	}
+	// 2. Walk the Universal Login pages until we are sent back to RADAR
+	submitted := ""
+	for step := 0; ; step++ {
+		page := resp.Request.URL
+		form, message, err := parseLoginPage(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return "", "", fmt.Errorf("failed to parse Jamf ID page %s: %w", page.Path, err)
+		}
+		tflog.SubsystemDebug(logCtx, subsystemAuth, "Jamf ID login page reached", map[string]interface{}{
+			"url":    logURL(page),
+			"status": resp.StatusCode,
+		})
 
-	// Fill in password
-	formData.Set("password", password)
-	formData.Set("action", "default")
+		if !strings.HasPrefix(page.Path, "/u/") {
+			break
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return "", "", fmt.Errorf("Jamf ID page %s returned status: %s", page.Path, resp.Status)
+		}
+		// Auth0 answers a rejected form by showing the same page again with an error on it
+		if page.Path == submitted {
+			return "", "", jamfIDStuckError(page.Path, message)
+		}
+		if step == jamfIDMaxSteps {
+			return "", "", fmt.Errorf("Jamf ID login did not finish after %d pages, last page %s", jamfIDMaxSteps, page.Path)
+		}
 
-	// 5. Post to /u/login/password
-	resp, err = client.PostForm(currentURL, formData)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to submit password: %w", err)
-	}
-	defer resp.Body.Close()
+		switch page.Path {
+		case "/u/login/identifier":
+			form.Set("username", username)
+		case "/u/login/password":
+			if form.Has("username") {
+				form.Set("username", username)
+			}
+			form.Set("password", password)
+		case "/u/mfa-otp-challenge":
+			if c.config.TotpSecret == "" {
+				return "", "", errors.New("Jamf ID asked for an authenticator app code: set totp_secret to the base32 secret of the authenticator enrolled for this account")
+			}
+			code, err := generateTOTP(c.config.TotpSecret, time.Now())
+			if err != nil {
+				return "", "", err
+			}
+			form.Set("code", code)
+		default:
+			return "", "", jamfIDPageError(page.Path, message)
+		}
+		form.Set("action", "default")
 
-	if resp.StatusCode != http.StatusOK {
-		if strings.Contains(resp.Request.URL.String(), "/u/login/password") {
-			return "", "", errors.New("login failed: invalid password or stuck at password step")
+		submitted = page.Path
+		req, err := http.NewRequestWithContext(ctx, "POST", page.String(), strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", "", err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err = client.Do(req)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to submit Jamf ID page %s: %w", page.Path, err)
 		}
-		return "", "", fmt.Errorf("password submission returned status: %s", resp.Status)
 	}
 
-	// 6. Final Redirect back to Radar
-	finalURL := resp.Request.URL.String()
-	if !strings.Contains(finalURL, domain) {
-		fmt.Printf("Warning: Final URL %s does not contain domain %s\n", finalURL, domain)
+	// 3. Final Redirect back to Radar
+	_, host := splitDomain(domain)
+	finalURL := resp.Request.URL
+	if finalURL.Host != host {
+		tflog.SubsystemWarn(logCtx, subsystemAuth, "Jamf ID login did not return to the JSC domain", map[string]interface{}{
+			"url":    logURL(finalURL),
+			"domain": host,
+		})
 	}
 
-	// 7. Extract cookies
-	u, _ := url.Parse(fmt.Sprintf("https://%s", domain))
+	// 4. Extract cookies
+	u, _ := url.Parse(domainURL(domain, "/"))
 	cookies := jar.Cookies(u)
 	var sessionCookie, xsrfToken string
 	for _, cookie := range cookies {
@@ -119,20 +211,58 @@ func AuthenticateViaJamfID(domain, username, password string) (string, string, e
 	}
 
 	if sessionCookie == "" {
-		return "", "", errors.New("SESSION cookie not found after login flow")
+		return "", "", fmt.Errorf("SESSION cookie not found after Jamf ID login, which ended at %s", logURL(finalURL))
 	}
+	tflog.SubsystemDebug(logCtx, subsystemAuth, "Logged in to the RADAR API with Jamf ID")
 
 	return sessionCookie, xsrfToken, nil
 }
 
-// extractFormData parses HTML and returns a url.Values map with all input fields (hidden and otherwise).
-func extractFormData(r io.Reader) (url.Values, error) {
+// jamfIDStuckError explains why a page was shown again after submitting it.
+func jamfIDStuckError(path, message string) error {
+	if message == "" {
+		message = "the page was shown again without an error message"
+	}
+	switch path {
+	case "/u/login/identifier":
+		return fmt.Errorf("Jamf ID rejected the username: %s", message)
+	case "/u/login/password":
+		return fmt.Errorf("Jamf ID rejected the password: %s", message)
+	case "/u/mfa-otp-challenge":
+		return fmt.Errorf("Jamf ID rejected the authenticator code, check totp_secret and the system clock: %s", message)
+	}
+	return fmt.Errorf("Jamf ID login is stuck at %s: %s", path, message)
+}
+
+// jamfIDPageError explains a login page the provider can not complete on its own.
+func jamfIDPageError(path, message string) error {
+	var err error
+	switch {
+	case strings.HasPrefix(path, "/u/mfa-") && strings.HasSuffix(path, "-enrollment"):
+		err = fmt.Errorf("Jamf ID requires MFA enrolment for this account (%s). Enrol an authenticator app in a browser and set totp_secret", path)
+	case strings.HasPrefix(path, "/u/mfa-"):
+		err = fmt.Errorf("Jamf ID asked for an MFA challenge the provider can not answer (%s). Only authenticator app codes are supported: enrol an authenticator app and set totp_secret, or use a local account", path)
+	case strings.HasPrefix(path, "/u/consent"):
+		err = fmt.Errorf("Jamf ID is asking to authorize JSC (%s). Log in once in a browser to accept it", path)
+	default:
+		err = fmt.Errorf("Jamf ID login stopped at an unexpected page %s", path)
+	}
+	if message != "" {
+		return fmt.Errorf("%w: %s", err, message)
+	}
+	return err
+}
+
+// parseLoginPage parses a login page and returns its input fields (hidden and otherwise) and
+// the error message shown on it, if any.
+func parseLoginPage(r io.Reader) (url.Values, string, error) {
 	doc, err := html.Parse(r)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	values := url.Values{}
+	var messages []string
 
 	var traverse func(*html.Node)
 	traverse = func(n *html.Node) {
@@ -156,11 +286,42 @@ func extractFormData(r io.Reader) (url.Values, error) {
 				}
 			}
 		}
+		if n.Type == html.ElementNode && isErrorElement(n) {
+			if text := strings.Join(strings.Fields(nodeText(n)), " "); text != "" {
+				messages = append(messages, text)
+			}
+			return
+		}
 		for c := n.FirstChild; c != nil; c = c.NextSibling {
 			traverse(c)
 		}
 	}
 
 	traverse(doc)
-	return values, nil
+	return values, strings.Join(messages, "; "), nil
+}
+
+// isErrorElement reports whether n is one of the elements Universal Login shows errors in.
+func isErrorElement(n *html.Node) bool {
+	for _, a := range n.Attr {
+		switch {
+		case a.Key == "id" && strings.HasPrefix(a.Val, "error-element"):
+			return true
+		case a.Key == "class" && (strings.Contains(a.Val, "error-message") || strings.Contains(a.Val, "ulp-alert")):
+			return true
+		}
+	}
+	return false
+}
+
+// nodeText returns the text content of n and its children.
+func nodeText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var text strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		text.WriteString(nodeText(c))
+	}
+	return text.String()
 }
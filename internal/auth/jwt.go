@@ -0,0 +1,53 @@
+// Copyright 2025, Jamf Software LLC.
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// pagTokenRefreshWindow is how long before expiry the PAG JWT is proactively renewed, so a
+// token never runs out between being checked and the request reaching the gateway.
+const pagTokenRefreshWindow = 60 * time.Second
+
+// jwtExpiry returns the exp claim of a JWT. The signature is not verified - the token is only
+// inspected to decide when to log in again.
+func jwtExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode JWT payload: %v", err)
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse JWT claims: %v", err)
+	}
+	if claims.Exp == 0 {
+		return time.Time{}, fmt.Errorf("JWT has no exp claim")
+	}
+
+	return time.Unix(claims.Exp, 0), nil
+}
+
+// pagTokenExpiring reports whether the current PAG JWT is missing or about to expire. Tokens
+// without a readable expiry are trusted until the gateway rejects them with a 401.
+func (c *Client) pagTokenExpiring() bool {
+	jwt, expiry, _ := c.session.pagSession()
+	if jwt == "" {
+		return true
+	}
+	if expiry.IsZero() {
+		return false
+	}
+	return time.Until(expiry) < pagTokenRefreshWindow
+}
@@ -0,0 +1,46 @@
+// Copyright 2025, Jamf Software LLC.
+package auth
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestJWTExpiry(t *testing.T) {
+	token := func(claims string) string {
+		enc := base64.RawURLEncoding
+		return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + enc.EncodeToString([]byte(claims)) + "."
+	}
+
+	tests := map[string]struct {
+		token   string
+		want    time.Time
+		wantErr bool
+	}{
+		"valid":          {token: token(`{"sub":"app","exp":1767225600}`), want: time.Unix(1767225600, 0)},
+		"padded payload": {token: "e30.eyJleHAiOiAxNzY3MjI1NjAwfQ==.", want: time.Unix(1767225600, 0)},
+		"malformed":      {token: "not-a-jwt", wantErr: true},
+		"two parts":      {token: "header.payload", wantErr: true},
+		"missing exp":    {token: token(`{"sub":"app"}`), wantErr: true},
+		"not base64":     {token: "header.!!!.signature", wantErr: true},
+		"not json":       {token: token(`exp=1767225600`), wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := jwtExpiry(tt.token)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("jwtExpiry = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("jwtExpiry: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("jwtExpiry = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
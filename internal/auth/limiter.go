@@ -0,0 +1,72 @@
+// Copyright 2025, Jamf Software LLC.
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter paces the requests of a Client and its customer-scoped copies. It is a token
+// bucket refilled at requests_per_second, holding a single token so requests are spread evenly
+// rather than sent in bursts, combined with a cap of max_concurrent_requests in flight.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // time between requests, 0 when unlimited
+	next     time.Time     // when the next token is available
+
+	slots chan struct{} // one entry per request in flight, nil when unlimited
+}
+
+// newRateLimiter returns a limiter for the given settings. Zero disables either limit.
+func newRateLimiter(requestsPerSecond float64, maxConcurrent int) *rateLimiter {
+	l := &rateLimiter{}
+	if requestsPerSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+	return l
+}
+
+// reserve takes the next token and returns how long to wait before using it.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	return wait
+}
+
+// cancel returns a token taken by reserve that was not used.
+func (l *rateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.next = l.next.Add(-l.interval)
+}
+
+// acquire waits for a token and a free slot, returning how long that took and a function that
+// frees the slot. It gives up when ctx is cancelled.
+func (l *rateLimiter) acquire(ctx context.Context) (time.Duration, func(), error) {
+	start := time.Now()
+	if l.interval > 0 {
+		if err := sleep(ctx, l.reserve()); err != nil {
+			l.cancel()
+			return 0, nil, err
+		}
+	}
+	if l.slots == nil {
+		return time.Since(start), func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return time.Since(start), func() { <-l.slots }, nil
+	case <-ctx.Done():
+		return 0, nil, ctx.Err()
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2025, Jamf Software LLC.
+package auth
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Log subsystems. Their level can be raised independently of the provider, e.g.
+// TF_LOG_PROVIDER_JSC_HTTP=TRACE, to debug requests without the rest of the provider output.
+const (
+	subsystemHTTP = "jsc_http"
+	subsystemAuth = "jsc_auth"
+)
+
+// sensitiveLogFields are masked in every log line whatever their value.
+var sensitiveLogFields = []string{
+	"authorization",
+	"cookie",
+	"set_cookie",
+	"session",
+	"xsrf_token",
+	"password",
+	"client_secret",
+	"token",
+	"totp",
+	"backup_code",
+}
+
+// withLogSubsystems adds the jsc_http and jsc_auth subsystems to ctx. Without a Terraform logger
+// in ctx, as in unit tests, this and every log call is a no-op.
+func withLogSubsystems(ctx context.Context) context.Context {
+	for _, subsystem := range []string{subsystemHTTP, subsystemAuth} {
+		ctx = tflog.NewSubsystem(ctx, subsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER", subsystem))
+		ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, subsystem, sensitiveLogFields...)
+	}
+	return ctx
+}
+
+// logContext returns the context to log under for a request carrying ctx. Requests built
+// without a context log under the client's own context, which carries the provider's logger.
+// Every secret the client holds, including the current session, is masked wherever it appears
+// in a message or field.
+func (c *Client) logContext(ctx context.Context) context.Context {
+	switch ctx {
+	case c.ctx, context.Background():
+		ctx = c.ctx
+	default:
+		ctx = withLogSubsystems(ctx)
+	}
+
+	sessionCookie, xsrfToken, _ := c.session.radarSession()
+	jwt, _, _ := c.session.pagSession()
+	secrets := []string{}
+	for _, secret := range []string{
+		c.config.Password,
+		c.config.Applicationsecret,
+		c.config.TotpSecret,
+		c.config.BackupCode,
+		sessionCookie,
+		xsrfToken,
+		jwt,
+	} {
+		// An empty string would match everywhere
+		if secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	for _, subsystem := range []string{subsystemHTTP, subsystemAuth} {
+		ctx = tflog.SubsystemMaskLogStrings(ctx, subsystem, secrets...)
+	}
+	return ctx
+}
+
+// logURL returns the scheme, host and path of u for logging. The query is left out as it can
+// carry customer IDs, emails and OAuth state.
+func logURL(u *url.URL) string {
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String()
+}
@@ -0,0 +1,74 @@
+// Copyright 2025, Jamf Software LLC.
+package auth_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"jsctfprovider/internal/auth"
+	"jsctfprovider/internal/fakejsc"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestLogsMaskSecrets(t *testing.T) {
+	t.Setenv("TF_LOG_PROVIDER_JSC_HTTP", "TRACE")
+	t.Setenv("TF_LOG_PROVIDER_JSC_AUTH", "TRACE")
+
+	s := fakejsc.New(t)
+	var output bytes.Buffer
+	c, err := auth.NewClient(tflogtest.RootLogger(context.Background(), &output), auth.Config{
+		DomainName:        s.URL,
+		PAGDomainName:     s.URL,
+		Username:          fakejsc.Username,
+		Password:          fakejsc.Password,
+		Customerid:        "empty",
+		Applicationid:     fakejsc.ApplicationID,
+		Applicationsecret: fakejsc.ApplicationSecret,
+		Retry:             auth.RetryPolicy{MaxRetries: 1, MinWait: time.Millisecond, MaxWait: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := c.AuthenticateRadarAPI(context.Background()); err != nil {
+		t.Fatalf("AuthenticateRadarAPI: %v", err)
+	}
+	if err := c.AuthenticatePAG(context.Background()); err != nil {
+		t.Fatalf("AuthenticatePAG: %v", err)
+	}
+	s.Fail("/gate/identity-service/v1/connections", http.StatusServiceUnavailable, 1)
+	do(t, c.MakeRequest, "GET", "https://radar.wandera.com/gate/identity-service/v1/connections", "")
+	do(t, c.MakePAGRequest, "GET", "https://api.wandera.com/ztna/v1/apps", "")
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("decoding log output: %v", err)
+	}
+	var sawRetry bool
+	for _, entry := range entries {
+		if entry["@module"] == "provider.jsc_http" && entry["@message"] == "Received HTTP response" &&
+			entry["path"] == "/gate/identity-service/v1/connections" && entry["attempt"] == float64(2) {
+			sawRetry = true
+			for _, field := range []string{"method", "status", "latency_ms"} {
+				if _, ok := entry[field]; !ok {
+					t.Errorf("response log is missing %s: %v", field, entry)
+				}
+			}
+		}
+	}
+	if !sawRetry {
+		t.Errorf("no jsc_http log for the retried request in %v", entries)
+	}
+
+	// The fake issues predictable session and XSRF tokens, check none of them leaked
+	logged := output.String()
+	for _, secret := range []string{fakejsc.Password, fakejsc.ApplicationSecret, "session-", "xsrf-", "customerId="} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("log output contains %q", secret)
+		}
+	}
+}
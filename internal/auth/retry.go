@@ -0,0 +1,234 @@
+// Copyright 2025, Jamf Software LLC.
+package auth
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// RetryPolicy controls how MakeRequest and MakePAGRequest retry failed calls.
+type RetryPolicy struct {
+	MaxRetries int           // retries after the first attempt
+	MinWait    time.Duration // backoff before the first retry
+	MaxWait    time.Duration // upper bound for the exponential backoff
+}
+
+// DefaultRetryPolicy holds the defaults of the provider retry settings. A Config without a
+// RetryPolicy does not retry, so max_retries = 0 means what it says.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 2,
+	MinWait:    1 * time.Second,
+	MaxWait:    30 * time.Second,
+}
+
+// backoff returns the jittered exponential wait before retry number attempt (starting at 1).
+// Half of the wait is fixed and half is random so parallel resources do not retry in lockstep.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.MinWait
+	for i := 1; i < attempt && wait < p.MaxWait; i++ {
+		wait *= 2
+	}
+	if wait > p.MaxWait {
+		wait = p.MaxWait
+	}
+	if wait <= 0 {
+		return 0
+	}
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// retryAfter parses the Retry-After header of a 429 or 503 response, which JSC sends as
+// either a number of seconds or an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// isIdempotent reports whether resending method can not apply a change twice.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryableStatus reports whether a status is worth retrying at all.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// notSent reports whether err happened before the request reached the server, in which case
+// even a POST can be resent safely.
+func notSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// doWithRetry sends req using the client's retry policy. prepare is called before every attempt
+// to attach the current credentials, and reauth is called at most once when the server answers
+// 401 or 403. Logging in again does not count as a retry, so an expired session is renewed even
+// when retries are off. Non-idempotent requests are only resent when the server cannot have
+// acted on them.
+// The final response is returned as-is so callers can report its status.
+func (c *Client) doWithRetry(req *http.Request, prepare func(*http.Request) error, reauth func() error) (*http.Response, error) {
+	policy := c.config.Retry
+	idempotent := isIdempotent(req.Method)
+	reauthenticated := false
+	retries := 0
+
+	for attempt := 0; ; attempt++ {
+		// Reset the request body for retries (body is consumed after first attempt)
+		if req.GetBody != nil {
+			req.Body, _ = req.GetBody()
+		}
+		if err := prepare(req); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.send(req, attempt+1)
+		retriesLeft := retries < policy.MaxRetries
+
+		if err != nil {
+			// A cancelled apply must not be retried
+			if !retriesLeft || (!idempotent && !notSent(err)) || req.Context().Err() != nil {
+				return nil, err
+			}
+			retries++
+			if err := sleep(req.Context(), policy.backoff(retries)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// Built after the attempt so a session renewed by prepare is masked too
+		ctx := c.logContext(req.Context())
+		fields := map[string]interface{}{
+			"method":  req.Method,
+			"path":    req.URL.Path,
+			"status":  resp.StatusCode,
+			"attempt": attempt + 1,
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			// The session may have expired - log in once and try again. Auth failures are rejected
+			// before the request is processed, so this is safe for POST too.
+			if reauthenticated {
+				return resp, nil
+			}
+			resp.Body.Close()
+			tflog.SubsystemWarn(ctx, subsystemAuth, "Request was not authorized, logging in again", fields)
+			if err := reauth(); err != nil {
+				return nil, err
+			}
+			reauthenticated = true
+			continue
+
+		case retryableStatus(resp.StatusCode):
+			wait, hasRetryAfter := retryAfter(resp)
+			// A POST that hit a 5xx may already have been applied, unless the server told us when to come back
+			if !retriesLeft || (!idempotent && !hasRetryAfter) {
+				return resp, nil
+			}
+			retries++
+			if !hasRetryAfter {
+				wait = policy.backoff(retries)
+			}
+			resp.Body.Close()
+			fields["retry_in"] = wait.String()
+			tflog.SubsystemWarn(ctx, subsystemHTTP, "Retrying HTTP request", fields)
+			if err := sleep(req.Context(), wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		return resp, nil
+	}
+}
+
+// sleep waits for d, returning early with the context error when ctx is cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// send makes a single attempt at req within the client's rate and concurrency limits and logs
+// it to the jsc_http subsystem. Every request the client makes goes through here, including the
+// logins.
+func (c *Client) send(req *http.Request, attempt int) (*http.Response, error) {
+	ctx := c.logContext(req.Context())
+	fields := map[string]interface{}{
+		"method":  req.Method,
+		"host":    req.URL.Host,
+		"path":    req.URL.Path,
+		"attempt": attempt,
+	}
+
+	// The slot is held until the response headers arrive, the body is read straight after
+	waited, release, err := c.limiter.acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	if waited > time.Millisecond {
+		fields["rate_limit_wait_ms"] = waited.Milliseconds()
+	}
+	tflog.SubsystemDebug(ctx, subsystemHTTP, "Sending HTTP request", fields)
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	release()
+	fields["latency_ms"] = time.Since(start).Milliseconds()
+
+	if err != nil {
+		fields["error"] = err.Error()
+		// Check if the error is a timeout error by checking for net.Error and the Timeout() method
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			tflog.SubsystemError(ctx, subsystemHTTP, "HTTP request timed out", fields)
+		} else {
+			tflog.SubsystemError(ctx, subsystemHTTP, "HTTP request failed", fields)
+		}
+		return nil, err
+	}
+
+	fields["status"] = resp.StatusCode
+	tflog.SubsystemDebug(ctx, subsystemHTTP, "Received HTTP response", fields)
+	return resp, nil
+}
@@ -0,0 +1,264 @@
+// Copyright 2025, Jamf Software LLC.
+package auth
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// sessionCacheIterations is the PBKDF2 work factor for the cache key. The key is derived at
+// most once per run, so the cost is paid once per plan rather than per request.
+const sessionCacheIterations = 600000
+
+// cachedSession is the plaintext of a session cache file.
+type cachedSession struct {
+	SessionCookie string    `json:"session_cookie,omitempty"`
+	XSRFToken     string    `json:"xsrf_token,omitempty"`
+	Customerid    string    `json:"customer_id,omitempty"`
+	AuthMethod    string    `json:"auth_method,omitempty"`
+	PAGJWT        string    `json:"pag_jwt,omitempty"`
+	PAGJWTExpiry  time.Time `json:"pag_jwt_expiry,omitempty"`
+}
+
+// sessionCacheFile is the on-disk form of a session cache. Data is the AES-GCM sealed
+// cachedSession, with the cache identity as additional data so files cannot be swapped.
+type sessionCacheFile struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// sessionCacheKey holds the derived encryption key, reused for every save in a run.
+type sessionCacheKey struct {
+	salt []byte
+	key  []byte
+}
+
+// sessionCacheIdentity identifies whose session a cache file holds: the domains, the user
+// and application, and the configured customer.
+func (c *Client) sessionCacheIdentity() string {
+	return strings.Join([]string{
+		c.config.DomainName,
+		c.config.Username,
+		c.config.Customerid,
+		c.config.PAGDomainName,
+		c.config.Applicationid,
+	}, "\x00")
+}
+
+// sessionCachePath returns the cache file for this client. The name is a hash so the
+// directory listing does not reveal domains or usernames.
+func (c *Client) sessionCachePath() string {
+	sum := sha256.Sum256([]byte(c.sessionCacheIdentity()))
+	return filepath.Join(c.config.SessionCacheDir, "jsc-session-"+hex.EncodeToString(sum[:16])+".json")
+}
+
+// sessionCacheAEAD derives the cache cipher from the configured secrets, so a cache can only
+// be read by someone who already holds the credentials it stands in for. Callers hold
+// session.cacheMu.
+func (c *Client) sessionCacheAEAD(salt []byte) (cipher.AEAD, error) {
+	if c.session.cacheKey == nil || string(c.session.cacheKey.salt) != string(salt) {
+		secret := c.config.Password + "\x00" + c.config.Applicationsecret
+		key, err := pbkdf2.Key(sha256.New, secret, salt, sessionCacheIterations, 32)
+		if err != nil {
+			return nil, err
+		}
+		c.session.cacheKey = &sessionCacheKey{salt: salt, key: key}
+	}
+	block, err := aes.NewCipher(c.session.cacheKey.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// readSessionCache returns the cached session, or nil when there is none or it cannot be
+// decrypted, for example because the password changed.
+func (c *Client) readSessionCache() *cachedSession {
+	if c.config.SessionCacheDir == "" {
+		return nil
+	}
+	ctx := c.logContext(c.ctx)
+
+	raw, err := os.ReadFile(c.sessionCachePath())
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			tflog.SubsystemWarn(ctx, subsystemAuth, "Unable to read the session cache", map[string]interface{}{"error": err.Error()})
+		}
+		return nil
+	}
+
+	var file sessionCacheFile
+	if err := json.Unmarshal(raw, &file); err != nil || file.Version != 1 {
+		tflog.SubsystemWarn(ctx, subsystemAuth, "Ignoring a session cache in an unknown format")
+		return nil
+	}
+	c.session.cacheMu.Lock()
+	aead, err := c.sessionCacheAEAD(file.Salt)
+	c.session.cacheMu.Unlock()
+	if err != nil || len(file.Nonce) != aead.NonceSize() {
+		tflog.SubsystemWarn(ctx, subsystemAuth, "Ignoring an unreadable session cache")
+		return nil
+	}
+	plaintext, err := aead.Open(nil, file.Nonce, file.Data, []byte(c.sessionCacheIdentity()))
+	if err != nil {
+		tflog.SubsystemInfo(ctx, subsystemAuth, "Ignoring a session cache encrypted with other credentials")
+		return nil
+	}
+
+	var cached cachedSession
+	if err := json.Unmarshal(plaintext, &cached); err != nil {
+		return nil
+	}
+	return &cached
+}
+
+// saveSessionCache writes the current session to the cache, replacing the file atomically so
+// concurrent runs never read a partial write. Failures are logged, as the cache is only an
+// optimisation.
+func (c *Client) saveSessionCache() {
+	if c.config.SessionCacheDir == "" {
+		return
+	}
+	if err := c.writeSessionCache(); err != nil {
+		tflog.SubsystemWarn(c.logContext(c.ctx), subsystemAuth, "Unable to write the session cache", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+func (c *Client) writeSessionCache() error {
+	// The RADAR and PAG logins can finish together, write one file at a time so the last
+	// write holds both
+	c.session.cacheMu.Lock()
+	defer c.session.cacheMu.Unlock()
+
+	c.session.mu.Lock()
+	plaintext, err := json.Marshal(cachedSession{
+		SessionCookie: c.session.sessionCookie,
+		XSRFToken:     c.session.xsrfToken,
+		Customerid:    c.session.customerid,
+		AuthMethod:    string(c.session.radarMethod),
+		PAGJWT:        c.session.pagjwt,
+		PAGJWTExpiry:  c.session.pagjwtExpiry,
+	})
+	c.session.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, 16)
+	if c.session.cacheKey != nil {
+		salt = c.session.cacheKey.salt
+	} else if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	aead, err := c.sessionCacheAEAD(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(sessionCacheFile{
+		Version: 1,
+		Salt:    salt,
+		Nonce:   nonce,
+		Data:    aead.Seal(nil, nonce, plaintext, []byte(c.sessionCacheIdentity())),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.config.SessionCacheDir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.config.SessionCacheDir, ".jsc-session-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.sessionCachePath())
+}
+
+// RestoreRadarSession loads the RADAR session from session_cache_dir and checks it is still
+// accepted with a single /auth/v1/me call. It reports false when the caller has to log in.
+func (c *Client) RestoreRadarSession(ctx context.Context) bool {
+	cached := c.readSessionCache()
+	if cached == nil || cached.SessionCookie == "" || cached.Customerid == "" {
+		return false
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", domainURL(c.config.DomainName, "/auth/v1/me"), nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Xsrf-Token", cached.XSRFToken)
+	req.AddCookie(&http.Cookie{Name: "SESSION", Value: cached.SessionCookie})
+	req.AddCookie(&http.Cookie{Name: "XSRF-TOKEN", Value: cached.XSRFToken})
+	resp, err := c.send(req, 1)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	ctx = c.logContext(ctx)
+	if resp.StatusCode != http.StatusOK {
+		tflog.SubsystemInfo(ctx, subsystemAuth, "Cached RADAR session has expired, logging in", map[string]interface{}{
+			"status": resp.StatusCode,
+		})
+		return false
+	}
+
+	method := AuthMethod(cached.AuthMethod)
+	if method == "" {
+		method = AuthMethodLocal // written before the login method was cached
+	}
+	c.session.setRadarSession(cached.SessionCookie, cached.XSRFToken, method)
+	c.session.setDefaultCustomerid(cached.Customerid)
+	tflog.SubsystemDebug(ctx, subsystemAuth, "Reusing cached RADAR session", map[string]interface{}{
+		"customer_id": cached.Customerid,
+	})
+	return true
+}
+
+// RestorePAGSession loads the PAG JWT from session_cache_dir. It reports false when there is no
+// cached token or it is about to expire.
+func (c *Client) RestorePAGSession() bool {
+	cached := c.readSessionCache()
+	if cached == nil || cached.PAGJWT == "" {
+		return false
+	}
+	if cached.PAGJWTExpiry.IsZero() || time.Until(cached.PAGJWTExpiry) < pagTokenRefreshWindow {
+		return false
+	}
+
+	c.session.setPAGSession(cached.PAGJWT, cached.PAGJWTExpiry)
+	tflog.SubsystemDebug(c.logContext(c.ctx), subsystemAuth, "Reusing cached PAG token", map[string]interface{}{
+		"expiry": cached.PAGJWTExpiry,
+	})
+	return true
+}
@@ -0,0 +1,40 @@
+// Copyright 2025, Jamf Software LLC.
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGenerateTOTP(t *testing.T) {
+	// The SHA-1 test vectors of RFC 6238 appendix B, whose seed is the ASCII string
+	// "12345678901234567890", cut to the last 6 of their 8 digits
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	tests := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range tests {
+		got, err := generateTOTP(secret, time.Unix(unix, 0))
+		if err != nil {
+			t.Fatalf("generateTOTP at %d: %v", unix, err)
+		}
+		if got != want {
+			t.Errorf("generateTOTP at %d = %s, want %s", unix, got, want)
+		}
+	}
+
+	// Secrets are accepted the way authenticator apps show them
+	for _, formatted := range []string{"gezd gnbv gy3t qojq gezd gnbv gy3t qojq", " GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ===="} {
+		if got, err := generateTOTP(formatted, time.Unix(59, 0)); err != nil || got != "287082" {
+			t.Errorf("generateTOTP(%q) = %s, %v, want 287082", formatted, got, err)
+		}
+	}
+	if _, err := generateTOTP("not base32!", time.Unix(59, 0)); err == nil {
+		t.Error("generateTOTP accepted a secret that is not base32")
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2025, Jamf Software LLC.
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DefaultRequestTimeout bounds a single HTTP request when request_timeout is not set.
+const DefaultRequestTimeout = 121 * time.Second
+
+// TransportConfig holds the HTTP settings shared by every request the provider makes, including
+// the RADAR and PAG logins and the Jamf ID browser flow.
+type TransportConfig struct {
+	ProxyURL           string        // proxy for all requests, otherwise HTTPS_PROXY/HTTP_PROXY/NO_PROXY apply
+	CACertFile         string        // PEM bundle trusted in addition to the system roots
+	CACertPEM          string        // PEM bundle trusted in addition to the system roots
+	InsecureSkipVerify bool          // disables TLS verification, only for test stand-ins
+	Timeout            time.Duration // per request, defaults to DefaultRequestTimeout
+	UserAgent          string        // sent on every request when set
+}
+
+// newHTTPClient builds the single HTTP client used by a Client.
+func newHTTPClient(ctx context.Context, config TransportConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.ProxyURL != "" {
+		proxy, err := url.Parse(config.ProxyURL)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy_url %q: must be an absolute URL such as http://proxy.example.com:3128", config.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.CACertFile != "" || config.CACertPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			tflog.SubsystemWarn(ctx, subsystemHTTP, "Unable to load the system certificate pool, only the configured CA bundle will be trusted", map[string]interface{}{
+				"error": err.Error(),
+			})
+			pool = x509.NewCertPool()
+		}
+		if config.CACertFile != "" {
+			pem, err := os.ReadFile(config.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read ca_cert_file: %v", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("ca_cert_file %s contains no PEM certificates", config.CACertFile)
+			}
+		}
+		if config.CACertPEM != "" && !pool.AppendCertsFromPEM([]byte(config.CACertPEM)) {
+			return nil, fmt.Errorf("ca_cert_pem contains no PEM certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if config.InsecureSkipVerify {
+		tflog.SubsystemWarn(ctx, subsystemHTTP, "TLS certificate verification is disabled by insecure_skip_verify")
+		tlsConfig.InsecureSkipVerify = true
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = DefaultRequestTimeout
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if config.UserAgent != "" {
+		roundTripper = &userAgentTransport{userAgent: config.UserAgent, next: transport}
+	}
+
+	return &http.Client{Transport: roundTripper, Timeout: timeout}, nil
+}
+
+// userAgentTransport sets the User-Agent header on every request.
+type userAgentTransport struct {
+	userAgent string
+	next      http.RoundTripper
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,190 @@
+// Copyright 2025, Jamf Software LLC.
+package auth_test
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"jsctfprovider/internal/auth"
+)
+
+// tokenServer answers the PAG login and records the User-Agent and URL of each request.
+type tokenServer struct {
+	mu         sync.Mutex
+	userAgents []string
+	urls       []string
+}
+
+func (s *tokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.userAgents = append(s.userAgents, r.Header.Get("User-Agent"))
+	s.urls = append(s.urls, r.URL.String())
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"token":"token"}`))
+}
+
+// loginPAG builds a client for domain with the given transport and logs in to the PAG API.
+func loginPAG(t *testing.T, domain string, transport auth.TransportConfig) error {
+	t.Helper()
+	c, err := auth.NewClient(context.Background(), auth.Config{
+		PAGDomainName:     domain,
+		Applicationid:     "id",
+		Applicationsecret: "secret",
+		Transport:         transport,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c.AuthenticatePAG(context.Background())
+}
+
+func TestTransportTrustsConfiguredCA(t *testing.T) {
+	handler := &tokenServer{}
+	s := httptest.NewTLSServer(handler)
+	t.Cleanup(s.Close)
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+
+	if err := loginPAG(t, s.URL, auth.TransportConfig{}); err == nil {
+		t.Fatal("login succeeded without trusting the test CA")
+	}
+
+	if err := loginPAG(t, s.URL, auth.TransportConfig{CACertPEM: string(caPEM)}); err != nil {
+		t.Errorf("login with ca_cert_pem: %v", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := loginPAG(t, s.URL, auth.TransportConfig{CACertFile: caFile}); err != nil {
+		t.Errorf("login with ca_cert_file: %v", err)
+	}
+
+	if err := loginPAG(t, s.URL, auth.TransportConfig{InsecureSkipVerify: true}); err != nil {
+		t.Errorf("login with insecure_skip_verify: %v", err)
+	}
+}
+
+func TestTransportSetsUserAgent(t *testing.T) {
+	handler := &tokenServer{}
+	s := httptest.NewServer(handler)
+	t.Cleanup(s.Close)
+
+	if err := loginPAG(t, s.URL, auth.TransportConfig{UserAgent: "terraform-provider-jsc/1.2.3"}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if len(handler.userAgents) != 1 || handler.userAgents[0] != "terraform-provider-jsc/1.2.3" {
+		t.Errorf("User-Agent = %q, want terraform-provider-jsc/1.2.3", handler.userAgents)
+	}
+}
+
+func TestTransportUsesProxy(t *testing.T) {
+	proxy := &tokenServer{}
+	s := httptest.NewServer(proxy)
+	t.Cleanup(s.Close)
+
+	// The target never resolves, so the login only succeeds if it goes through the proxy
+	if err := loginPAG(t, "http://jsc.invalid", auth.TransportConfig{ProxyURL: s.URL}); err != nil {
+		t.Fatalf("login through proxy: %v", err)
+	}
+	if len(proxy.urls) != 1 || proxy.urls[0] != "http://jsc.invalid/v1/login" {
+		t.Errorf("proxy received %q, want http://jsc.invalid/v1/login", proxy.urls)
+	}
+}
+
+func TestNewClientRejectsInvalidTransport(t *testing.T) {
+	tests := map[string]auth.TransportConfig{
+		"relative proxy":  {ProxyURL: "proxy.example.com"},
+		"missing ca file": {CACertFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"empty ca pem":    {CACertPEM: "not a certificate"},
+	}
+	for name, transport := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := auth.NewClient(context.Background(), auth.Config{Transport: transport}); err == nil {
+				t.Error("NewClient succeeded, want an error")
+			}
+		})
+	}
+}
+
+// slowServer answers the PAG login and sleeps on every other request, recording the most
+// requests it had in flight at once.
+type slowServer struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (s *slowServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Path == "/v1/login" {
+		w.Write([]byte(`{"token":"token"}`))
+		return
+	}
+	s.mu.Lock()
+	s.inFlight++
+	s.maxInFlight = max(s.maxInFlight, s.inFlight)
+	s.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	s.mu.Lock()
+	s.inFlight--
+	s.mu.Unlock()
+	w.Write([]byte(`{}`))
+}
+
+func TestClientLimitsRequests(t *testing.T) {
+	handler := &slowServer{}
+	s := httptest.NewServer(handler)
+	t.Cleanup(s.Close)
+
+	c, err := auth.NewClient(context.Background(), auth.Config{
+		PAGDomainName:         s.URL,
+		Applicationid:         "id",
+		Applicationsecret:     "secret",
+		RequestsPerSecond:     50,
+		MaxConcurrentRequests: 2,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := c.AuthenticatePAG(context.Background()); err != nil {
+		t.Fatalf("AuthenticatePAG: %v", err)
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest("GET", s.URL+"/ztna/v1/apps", nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp, err := c.MakePAGRequest(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if handler.maxInFlight != 2 {
+		t.Errorf("server saw %d requests in flight, want 2", handler.maxInFlight)
+	}
+	// The login took the first token, so the 8 requests need 8 more at 20ms apart
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("8 requests at 50 per second took %v, want at least 140ms", elapsed)
+	}
+}
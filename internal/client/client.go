@@ -0,0 +1,118 @@
+// Copyright 2025, Jamf Software LLC.
+
+// Package client provides typed JSON helpers on top of the session-aware request
+// functions of auth.Client, so every endpoint builds requests, checks statuses and
+// reports errors the same way.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Doer sends a request with the provider session attached, e.g. auth.Client.MakeRequest
+// for RADAR endpoints or auth.Client.MakePAGRequest for PAG endpoints.
+type Doer func(*http.Request) (*http.Response, error)
+
+// Get fetches endpoint and decodes the JSON response into a T.
+func Get[T any](ctx context.Context, do Doer, endpoint Endpoint) (*T, error) {
+	body, err := Send(ctx, do, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	return decode[T](http.MethodGet, endpoint, body)
+}
+
+// Post sends body as JSON and decodes the response into a T. An empty response decodes to the zero T.
+func Post[T any](ctx context.Context, do Doer, endpoint Endpoint, body interface{}) (*T, error) {
+	resp, err := Send(ctx, do, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	return decode[T](http.MethodPost, endpoint, resp)
+}
+
+// Put sends body as JSON, ignoring any response body.
+func Put(ctx context.Context, do Doer, endpoint Endpoint, body interface{}) error {
+	_, err := Send(ctx, do, http.MethodPut, endpoint, body)
+	return err
+}
+
+// Patch sends body as JSON, ignoring any response body.
+func Patch(ctx context.Context, do Doer, endpoint Endpoint, body interface{}) error {
+	_, err := Send(ctx, do, http.MethodPatch, endpoint, body)
+	return err
+}
+
+// Delete deletes the object at endpoint.
+func Delete(ctx context.Context, do Doer, endpoint Endpoint) error {
+	_, err := Send(ctx, do, http.MethodDelete, endpoint, nil)
+	return err
+}
+
+// Send is the building block of the helpers above. body is marshalled to JSON unless it is
+// nil or already a []byte. Any 2xx status is a success and the raw response body is returned;
+// anything else is returned as an *Error. The request is cancelled when ctx is, which includes
+// retries and backoff inside do.
+func Send(ctx context.Context, do Doer, method string, endpoint Endpoint, body interface{}) ([]byte, error) {
+	url, err := endpoint.Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s request: %w", method, err)
+	}
+
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		reader = bytes.NewReader(b)
+	default:
+		payload, err := json.Marshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s %s request: %w", method, endpoint, err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s %s request: %w", method, endpoint, err)
+	}
+	resp, err := do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w", method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s %s response: %w", method, req.URL.Path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &Error{
+			Method:     method,
+			Path:       req.URL.Path,
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       string(respBody),
+		}
+		apiErr.parseBody()
+		return nil, apiErr
+	}
+	return respBody, nil
+}
+
+func decode[T any](method string, endpoint Endpoint, body []byte) (*T, error) {
+	var out T
+	if len(bytes.TrimSpace(body)) == 0 {
+		return &out, nil
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse %s %s response: %w", method, endpoint, err)
+	}
+	return &out, nil
+}
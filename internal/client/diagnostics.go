@@ -0,0 +1,154 @@
+// Copyright 2025, Jamf Software LLC.
+package client
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// AttributePaths maps the request fields JSC names in validation errors, with any list indexes
+// removed, to the schema attribute each was built from. Dots separate nested attributes and #
+// stands for the next list index in the field, e.g. "routing.routeId": "routingid" or
+// "groupOverrides.routingOverrides.routing.routeId": "group_routing_overrides.#.routing_id".
+// When each element of a list comes from a different attribute, a key can keep its index, e.g.
+// "threatCategories[3]": "os_jailbreak_severity"; it then matches that element and every field
+// under it.
+type AttributePaths map[string]string
+
+var fieldIndex = regexp.MustCompile(`\[(\d+)\]`)
+
+// path returns the schema path of a JSC field, so a diagnostic for hostnames[2] points at the
+// rejected element. A field without the index ends the path at the list.
+func (p AttributePaths) path(field string) (cty.Path, bool) {
+	if attribute, ok := p.element(field); ok {
+		var path cty.Path
+		for _, step := range strings.Split(attribute, ".") {
+			path = path.GetAttr(step)
+		}
+		return path, true
+	}
+	attribute, ok := p[fieldIndex.ReplaceAllString(field, "")]
+	if !ok {
+		return nil, false
+	}
+	var indexes []int
+	for _, match := range fieldIndex.FindAllStringSubmatch(field, -1) {
+		index, _ := strconv.Atoi(match[1])
+		indexes = append(indexes, index)
+	}
+
+	var path cty.Path
+	for _, step := range strings.Split(attribute, ".") {
+		if step != "#" {
+			path = path.GetAttr(step)
+			continue
+		}
+		if len(indexes) == 0 {
+			break
+		}
+		path = path.IndexInt(indexes[0])
+		indexes = indexes[1:]
+	}
+	return path, true
+}
+
+// element returns the attribute of the longest key with a list index that field is, or is a
+// field under.
+func (p AttributePaths) element(field string) (string, bool) {
+	for key := field; key != ""; {
+		if attribute, ok := p[key]; ok && fieldIndex.MatchString(key) {
+			return attribute, true
+		}
+		cut := strings.LastIndexAny(key, ".[")
+		if cut < 0 {
+			break
+		}
+		key = key[:cut]
+	}
+	return "", false
+}
+
+// Diagnostics reports err from a JSC call as diagnostics starting with summary. Each field
+// error in a JSC error body becomes its own diagnostic, attached to the attribute paths maps it
+// to, so Terraform shows the offending line of configuration. The message and trace ID JSC
+// returned are kept so failures can be matched to JSC's own logs. paths may be nil.
+func Diagnostics(err error, summary string, paths AttributePaths) diag.Diagnostics {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return diag.FromErr(fmt.Errorf("%s: %w", summary, err))
+	}
+
+	detail := fmt.Sprintf("%s %s returned %s.", apiErr.Method, apiErr.Path, apiErr.Status)
+	if apiErr.TraceID != "" {
+		detail += " Trace ID: " + apiErr.TraceID
+	}
+
+	if len(apiErr.FieldErrors) == 0 {
+		message := apiErr.Message
+		if message == "" {
+			// Not JSC's error format, the raw body is the best there is
+			message = apiErr.Error()
+		}
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  summary + ": " + message,
+			Detail:   detail,
+		}}
+	}
+
+	var diags diag.Diagnostics
+	for _, fe := range apiErr.FieldErrors {
+		d := diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  summary + ": " + fe.Message,
+			Detail:   detail,
+		}
+		if apiErr.Message != "" {
+			d.Detail = apiErr.Message + ". " + detail
+		}
+		if path, ok := paths.path(fe.Field); ok {
+			d.AttributePath = path
+		} else if fe.Field != "" {
+			d.Summary = fmt.Sprintf("%s: %s %s", summary, fe.Field, fe.Message)
+		}
+		diags = append(diags, d)
+	}
+	return diags
+}
+
+// FrameworkDiagnostics is Diagnostics for resources served by terraform-plugin-framework.
+func FrameworkDiagnostics(err error, summary string, paths AttributePaths) fwdiag.Diagnostics {
+	var diags fwdiag.Diagnostics
+	for _, d := range Diagnostics(err, summary, paths) {
+		if d.AttributePath == nil {
+			diags.AddError(d.Summary, d.Detail)
+			continue
+		}
+		diags.AddAttributeError(frameworkPath(d.AttributePath), d.Summary, d.Detail)
+	}
+	return diags
+}
+
+// frameworkPath converts a path built by AttributePaths, which only holds attribute names and
+// list indexes.
+func frameworkPath(p cty.Path) path.Path {
+	var fp path.Path
+	for _, step := range p {
+		switch step := step.(type) {
+		case cty.GetAttrStep:
+			fp = fp.AtName(step.Name)
+		case cty.IndexStep:
+			index, _ := step.Key.AsBigFloat().Int64()
+			fp = fp.AtListIndex(int(index))
+		}
+	}
+	return fp
+}
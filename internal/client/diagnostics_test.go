@@ -0,0 +1,151 @@
+// Copyright 2025, Jamf Software LLC.
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"jsctfprovider/internal/client"
+
+	"github.com/hashicorp/go-cty/cty"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// failWith returns the error client.Send gets from a server answering status and body.
+func failWith(t *testing.T, status int, body string) error {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(s.Close)
+	_, err := client.Send(context.Background(), http.DefaultClient.Do, http.MethodPost, client.URL(s.URL+"/v1/apps").Customer(client.NoCustomer), map[string]string{"name": "app"})
+	if err == nil {
+		t.Fatal("Send succeeded, want an error")
+	}
+	return err
+}
+
+func TestDiagnosticsPointAtAttributes(t *testing.T) {
+	err := failWith(t, http.StatusBadRequest, `{
+		"status": 400,
+		"error": "Bad Request",
+		"message": "Validation failed",
+		"traceId": "trace-1",
+		"errors": [
+			{"field": "hostnames[2]", "defaultMessage": "must be a valid hostname"},
+			{"field": "groupOverrides.routingOverrides[1].routing.routeId", "defaultMessage": "route does not exist"},
+			{"field": "routing", "defaultMessage": "is invalid"}
+		]
+	}`)
+	paths := client.AttributePaths{
+		"hostnames": "hostnames.#",
+		"groupOverrides.routingOverrides.routing.routeId": "group_routing_overrides.#.routing_id",
+	}
+
+	diags := client.Diagnostics(err, "failed to create app", paths)
+	if len(diags) != 3 {
+		t.Fatalf("got %d diagnostics, want 3: %v", len(diags), diags)
+	}
+	want := []struct {
+		summary string
+		path    cty.Path
+	}{
+		{"failed to create app: must be a valid hostname", cty.GetAttrPath("hostnames").IndexInt(2)},
+		{"failed to create app: route does not exist", cty.GetAttrPath("group_routing_overrides").IndexInt(1).GetAttr("routing_id")},
+		{"failed to create app: routing is invalid", nil},
+	}
+	for i, d := range diags {
+		if d.Summary != want[i].summary {
+			t.Errorf("diagnostic %d summary = %q, want %q", i, d.Summary, want[i].summary)
+		}
+		if !d.AttributePath.Equals(want[i].path) {
+			t.Errorf("diagnostic %d path = %#v, want %#v", i, d.AttributePath, want[i].path)
+		}
+		if d.Detail != "Validation failed. POST /v1/apps returned 400 Bad Request. Trace ID: trace-1" {
+			t.Errorf("diagnostic %d detail = %q", i, d.Detail)
+		}
+	}
+}
+
+func TestDiagnosticsPointAtListElements(t *testing.T) {
+	err := failWith(t, http.StatusBadRequest, `{
+		"message": "Validation failed",
+		"errors": [
+			{"field": "threatCategories[1].action.reportingPolicy.severity", "defaultMessage": "must not be null"},
+			{"field": "threatCategories[0]", "defaultMessage": "is invalid"},
+			{"field": "threatCategories[12].action", "defaultMessage": "is invalid"}
+		]
+	}`)
+	paths := client.AttributePaths{
+		"threatCategories[0]": "os_jailbreak_severity",
+		"threatCategories[1]": "risky_hotspot_severity",
+	}
+
+	diags := client.Diagnostics(err, "failed to update policy", paths)
+	want := []cty.Path{
+		cty.GetAttrPath("risky_hotspot_severity"),
+		cty.GetAttrPath("os_jailbreak_severity"),
+		nil,
+	}
+	if len(diags) != len(want) {
+		t.Fatalf("got %d diagnostics, want %d: %v", len(diags), len(want), diags)
+	}
+	for i, d := range diags {
+		if !d.AttributePath.Equals(want[i]) {
+			t.Errorf("diagnostic %d path = %#v, want %#v", i, d.AttributePath, want[i])
+		}
+	}
+}
+
+func TestFrameworkDiagnostics(t *testing.T) {
+	err := failWith(t, http.StatusBadRequest, `{
+		"message": "Validation failed",
+		"errors": [
+			{"field": "groupOverrides.routingOverrides[1].routing.routeId", "defaultMessage": "route does not exist"},
+			{"field": "routing", "defaultMessage": "is invalid"}
+		]
+	}`)
+	paths := client.AttributePaths{
+		"groupOverrides.routingOverrides.routing.routeId": "group_routing_overrides.#.routing_id",
+	}
+
+	diags := client.FrameworkDiagnostics(err, "failed to create app", paths)
+	if len(diags) != 2 {
+		t.Fatalf("got %d diagnostics, want 2: %v", len(diags), diags)
+	}
+	want := path.Root("group_routing_overrides").AtListIndex(1).AtName("routing_id")
+	if d, ok := diags[0].(fwdiag.DiagnosticWithPath); !ok || !d.Path().Equal(want) {
+		t.Errorf("diagnostic 0 = %#v, want one at %s", diags[0], want)
+	}
+	if _, ok := diags[1].(fwdiag.DiagnosticWithPath); ok || diags[1].Summary() != "failed to create app: routing is invalid" {
+		t.Errorf("diagnostic 1 = %#v, want one without a path", diags[1])
+	}
+}
+
+func TestDiagnosticsWithoutFieldErrors(t *testing.T) {
+	err := failWith(t, http.StatusConflict, `{"status":409,"error":"Conflict","message":"name already in use","trace_id":"trace-2"}`)
+	diags := client.Diagnostics(err, "failed to create app", nil)
+	if len(diags) != 1 || diags[0].Summary != "failed to create app: name already in use" ||
+		diags[0].Detail != "POST /v1/apps returned 409 Conflict. Trace ID: trace-2" {
+		t.Errorf("diagnostics = %#v", diags)
+	}
+	if !errors.Is(err, client.ErrConflict) {
+		t.Errorf("errors.Is(%v, ErrConflict) = false", err)
+	}
+
+	err = failWith(t, http.StatusBadGateway, "<html>Bad Gateway</html>")
+	diags = client.Diagnostics(err, "failed to create app", nil)
+	if len(diags) != 1 || diags[0].Summary != "failed to create app: POST /v1/apps returned 502 Bad Gateway: <html>Bad Gateway</html>" {
+		t.Errorf("diagnostics = %#v", diags)
+	}
+
+	diags = client.Diagnostics(errors.New("connection refused"), "failed to create app", nil)
+	if len(diags) != 1 || diags[0].Summary != "failed to create app: connection refused" {
+		t.Errorf("diagnostics = %#v", diags)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2025, Jamf Software LLC.
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// CustomerScope says where a request carries the customer it is sent for.
+type CustomerScope int
+
+const (
+	// CustomerQuery adds the customer as the customerId query parameter, which is what most
+	// RADAR services expect.
+	CustomerQuery CustomerScope = iota
+	// CustomerPath fills in the {customerid} segment of the path template.
+	CustomerPath
+	// NoCustomer leaves the customer out, for endpoints that are not customer specific. Every
+	// PAG endpoint is one of these.
+	NoCustomer
+)
+
+// CustomerPlaceholder marks where the customer goes in a built URL. auth.Client.MakeRequest
+// replaces it with the customer of the client, so endpoints never need to know it.
+const CustomerPlaceholder = "{customerid}"
+
+type param struct {
+	name  string
+	value string
+}
+
+// Endpoint is a request URL built from a template such as
+// "https://radar.wandera.com/gate/traffic-routing-service/v1/apps/{id}". Path parameters are
+// written {name} in the template and escaped when they are filled in, so IDs, hostnames and
+// emails can never change the path. Endpoints are values, every method returns a copy.
+type Endpoint struct {
+	template string
+	params   []param
+	query    []param
+	customer CustomerScope
+}
+
+// URL returns the endpoint at template, scoped to the customer with the customerId query
+// parameter until Customer says otherwise.
+func URL(template string) Endpoint {
+	return Endpoint{template: template}
+}
+
+// Param fills in the {name} path parameter with value.
+func (e Endpoint) Param(name, value string) Endpoint {
+	e.params = append(e.params[:len(e.params):len(e.params)], param{name, value})
+	return e
+}
+
+// Query adds a query parameter. Parameters are sent in the order they are added.
+func (e Endpoint) Query(name, value string) Endpoint {
+	e.query = append(e.query[:len(e.query):len(e.query)], param{name, value})
+	return e
+}
+
+// Customer sets where the request carries the customer.
+func (e Endpoint) Customer(scope CustomerScope) Endpoint {
+	e.customer = scope
+	return e
+}
+
+// String returns the template, which is what error messages refer to.
+func (e Endpoint) String() string {
+	return e.template
+}
+
+// Build returns the URL of the endpoint. It fails when the template has a parameter that was
+// not filled in or a literal query, or when {customerid} is in the path without CustomerPath.
+func (e Endpoint) Build() (string, error) {
+	if strings.Contains(e.template, "?") {
+		return "", fmt.Errorf("%s: query parameters must be added with Query", e.template)
+	}
+	if strings.Contains(e.template, CustomerPlaceholder) != (e.customer == CustomerPath) {
+		return "", fmt.Errorf("%s: the path must contain %s exactly when the customer is in the path", e.template, CustomerPlaceholder)
+	}
+
+	var b strings.Builder
+	rest := e.template
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			b.WriteString(rest)
+			break
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("%s: unterminated path parameter", e.template)
+		}
+		end += start
+		b.WriteString(rest[:start])
+		name := rest[start+1 : end]
+		if name == "customerid" {
+			// Escaped, so the path stays valid while other parameters are escaped too
+			b.WriteString(url.PathEscape(CustomerPlaceholder))
+		} else {
+			value, ok := e.param(name)
+			if !ok {
+				return "", fmt.Errorf("%s: path parameter %s is not set", e.template, name)
+			}
+			if value == "" {
+				return "", fmt.Errorf("%s: path parameter %s is empty", e.template, name)
+			}
+			b.WriteString(url.PathEscape(value))
+		}
+		rest = rest[end+1:]
+	}
+
+	query := make([]string, 0, len(e.query)+1)
+	for _, p := range e.query {
+		query = append(query, url.QueryEscape(p.name)+"="+url.QueryEscape(p.value))
+	}
+	if e.customer == CustomerQuery {
+		query = append(query, "customerId="+CustomerPlaceholder)
+	}
+	if len(query) > 0 {
+		b.WriteString("?" + strings.Join(query, "&"))
+	}
+	return b.String(), nil
+}
+
+func (e Endpoint) param(name string) (string, bool) {
+	for _, p := range e.params {
+		if p.name == name {
+			return p.value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,69 @@
+// Copyright 2025, Jamf Software LLC.
+package client_test
+
+import (
+	"testing"
+
+	"jsctfprovider/internal/client"
+)
+
+func TestEndpointBuild(t *testing.T) {
+	apps := client.URL("https://radar.wandera.com/gate/traffic-routing-service/v1/apps/{id}")
+	admins := client.URL("https://radar.wandera.com/gate/admin-service/v4/customers/{customerid}/admins").Customer(client.CustomerPath)
+
+	tests := map[string]struct {
+		endpoint client.Endpoint
+		want     string
+	}{
+		"query scoped by default": {
+			endpoint: apps.Param("id", "app-1"),
+			want:     "https://radar.wandera.com/gate/traffic-routing-service/v1/apps/app-1?customerId={customerid}",
+		},
+		"path scoped": {
+			endpoint: admins.Query("page", "0").Query("pageSize", "100"),
+			want:     "https://radar.wandera.com/gate/admin-service/v4/customers/%7Bcustomerid%7D/admins?page=0&pageSize=100",
+		},
+		"not scoped": {
+			endpoint: client.URL("https://api.wandera.com/ztna/v1/apps").Customer(client.NoCustomer),
+			want:     "https://api.wandera.com/ztna/v1/apps",
+		},
+		"values are escaped": {
+			endpoint: apps.Param("id", "a/b c?d").Query("email", "admin+1@example.com").Query("host", "*.example.com&x=1"),
+			want:     "https://radar.wandera.com/gate/traffic-routing-service/v1/apps/a%2Fb%20c%3Fd?email=admin%2B1%40example.com&host=%2A.example.com%26x%3D1&customerId={customerid}",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.endpoint.Build()
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Build = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Endpoints are values, a parameter set on a copy must not leak into the original
+	apps.Param("id", "app-2")
+	if _, err := apps.Build(); err == nil {
+		t.Error("Param changed the endpoint it was called on")
+	}
+}
+
+func TestEndpointBuildErrors(t *testing.T) {
+	tests := map[string]client.Endpoint{
+		"missing parameter":           client.URL("https://radar.wandera.com/gate/v1/apps/{id}"),
+		"empty parameter":             client.URL("https://radar.wandera.com/gate/v1/apps/{id}").Param("id", ""),
+		"literal query":               client.URL("https://radar.wandera.com/gate/v1/apps?sort=name"),
+		"customer in path not scoped": client.URL("https://radar.wandera.com/gate/v1/customers/{customerid}"),
+		"path scoped without segment": client.URL("https://radar.wandera.com/gate/v1/apps").Customer(client.CustomerPath),
+	}
+	for name, endpoint := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got, err := endpoint.Build(); err == nil {
+				t.Errorf("Build = %q, want an error", got)
+			}
+		})
+	}
+}
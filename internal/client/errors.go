@@ -0,0 +1,112 @@
+// Copyright 2025, Jamf Software LLC.
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors for the statuses endpoints handle specially. Match them with errors.Is;
+// the *Error wrapping them carries the status and the body JSC returned.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// Error is returned when JSC answers with a non-2xx status. Message, FieldErrors and TraceID
+// are parsed from the body when it is JSC's JSON error format.
+type Error struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Status     string
+	Body       string // raw error body as returned by JSC
+
+	Message     string
+	FieldErrors []FieldError
+	TraceID     string
+}
+
+// FieldError is a validation error JSC reports against a single request field, e.g.
+// hostnames[2] or routing.routeId.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// errorBody covers the error formats of the JSC services: the Spring Boot default with its
+// binding errors, and the fieldErrors list some services return instead.
+type errorBody struct {
+	Message    string `json:"message"`
+	Error      string `json:"error"`
+	TraceID    string `json:"traceId"`
+	TraceIDAlt string `json:"trace_id"`
+	Errors     []struct {
+		Field          string `json:"field"`
+		Message        string `json:"message"`
+		DefaultMessage string `json:"defaultMessage"`
+	} `json:"errors"`
+	FieldErrors []struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	} `json:"fieldErrors"`
+}
+
+// parseBody fills Message, FieldErrors and TraceID from Body. Bodies that are not JSON, such
+// as gateway error pages, leave them empty.
+func (e *Error) parseBody() {
+	var body errorBody
+	if err := json.Unmarshal([]byte(e.Body), &body); err != nil {
+		return
+	}
+	e.Message = body.Message
+	if e.Message == "" {
+		e.Message = body.Error
+	}
+	e.TraceID = body.TraceID
+	if e.TraceID == "" {
+		e.TraceID = body.TraceIDAlt
+	}
+	for _, fe := range body.Errors {
+		message := fe.Message
+		if message == "" {
+			message = fe.DefaultMessage
+		}
+		if fe.Field != "" || message != "" {
+			e.FieldErrors = append(e.FieldErrors, FieldError{Field: fe.Field, Message: message})
+		}
+	}
+	for _, fe := range body.FieldErrors {
+		e.FieldErrors = append(e.FieldErrors, FieldError{Field: fe.Field, Message: fe.Message})
+	}
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s %s returned %s", e.Method, e.Path, e.Status)
+	if body := strings.TrimSpace(e.Body); body != "" {
+		msg += ": " + body
+	}
+	return msg
+}
+
+// Unwrap maps the status onto the sentinel errors so errors.Is(err, ErrNotFound) works.
+func (e *Error) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// IsNotFound reports whether err is a 404 from JSC. Reads use it to drop deleted objects from state.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
@@ -0,0 +1,186 @@
+// Copyright 2025, Jamf Software LLC.
+package fakejsc
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+// registerAuth serves the RADAR login flow and the PAG token endpoint.
+func (s *Server) registerAuth(mux *http.ServeMux) {
+	mux.HandleFunc("GET /auth/v1/login-methods", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		xsrf := s.token("xsrf")
+		s.xsrf[xsrf] = true
+		s.mu.Unlock()
+
+		http.SetCookie(w, &http.Cookie{Name: "XSRF-TOKEN", Value: xsrf, Path: "/"})
+		if r.URL.Query().Get("email") == JamfIDUsername {
+			writeJSON(w, http.StatusOK, object{"methods": []object{{"type": "JAMF_ID", "authorizationUrl": JamfIDAuthorizationPath}}})
+			return
+		}
+		writeJSON(w, http.StatusOK, object{"methods": []string{"PASSWORD"}})
+	})
+
+	mux.HandleFunc("POST /auth/v1/credentials", func(w http.ResponseWriter, r *http.Request) {
+		var credentials struct {
+			Username   string `json:"username"`
+			Password   string `json:"password"`
+			TOTP       string `json:"totp"`
+			BackupCode string `json:"backupCode"`
+		}
+		if !readJSON(w, r, &credentials) {
+			return
+		}
+		if !s.validXSRF(r) {
+			writeError(w, http.StatusForbidden, "invalid CSRF token")
+			return
+		}
+		if credentials.Username != Username || credentials.Password != Password {
+			writeError(w, http.StatusUnauthorized, "bad credentials")
+			return
+		}
+
+		s.mu.Lock()
+		if s.totpSecret != "" && !validTOTP(s.totpSecret, credentials.TOTP) {
+			if credentials.BackupCode != BackupCode || s.backupCodeUsed {
+				s.mu.Unlock()
+				writeError(w, http.StatusUnauthorized, "invalid MFA code")
+				return
+			}
+			s.backupCodeUsed = true
+		}
+		session := s.token("session")
+		s.sessions[session] = true
+		s.mu.Unlock()
+
+		http.SetCookie(w, &http.Cookie{Name: "SESSION", Value: session, Path: "/", HttpOnly: true})
+		writeJSON(w, http.StatusOK, object{})
+	})
+
+	mux.HandleFunc("GET /auth/v1/me", s.radar(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		admin := object{
+			"id":          AdminID,
+			"username":    Username,
+			"roles":       []string{"ADMIN"},
+			"permissions": []string{"READ", "WRITE"},
+			"entityType":  "CUSTOMER",
+			"entityId":    CustomerID,
+		}
+		for _, customer := range s.customers {
+			if !customer.Leaf {
+				admin["entityType"] = "PARENT"
+				admin["entityId"] = customer.ID
+				break
+			}
+		}
+		writeJSON(w, http.StatusOK, object{"admin": admin})
+	}))
+
+	mux.HandleFunc("POST /v1/login", func(w http.ResponseWriter, r *http.Request) {
+		basic := base64.StdEncoding.EncodeToString([]byte(ApplicationID + ":" + ApplicationSecret))
+		if r.Header.Get("Authorization") != "Basic "+basic {
+			writeError(w, http.StatusUnauthorized, "bad application credentials")
+			return
+		}
+
+		s.mu.Lock()
+		token := s.jwt()
+		s.pagTokens[token] = true
+		s.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, object{"token": token})
+	})
+}
+
+// validXSRF reports whether the X-Xsrf-Token header matches an issued XSRF-TOKEN cookie.
+func (s *Server) validXSRF(r *http.Request) bool {
+	cookie, err := r.Cookie("XSRF-TOKEN")
+	if err != nil || cookie.Value != r.Header.Get("X-Xsrf-Token") {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.xsrf[cookie.Value]
+}
+
+// radar wraps a RADAR handler so it is only reached with a live session and, when the
+// request is scoped with customerId, a customer visible to the admin.
+func (s *Server) radar(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		session, err := r.Cookie("SESSION")
+		s.mu.Lock()
+		live := err == nil && s.sessions[session.Value]
+		s.mu.Unlock()
+		if !live {
+			writeError(w, http.StatusUnauthorized, "session expired")
+			return
+		}
+		if !s.validXSRF(r) {
+			writeError(w, http.StatusForbidden, "invalid CSRF token")
+			return
+		}
+		if id := r.URL.Query().Get("customerId"); id != "" && !s.visible(id) {
+			writeError(w, http.StatusForbidden, "customer "+id+" is not visible to the admin")
+			return
+		}
+		if id := r.PathValue("customer"); id != "" && !s.visible(id) {
+			writeError(w, http.StatusForbidden, "customer "+id+" is not visible to the admin")
+			return
+		}
+		h(w, r)
+	}
+}
+
+// pag wraps a PAG handler so it is only reached with a live bearer token.
+func (s *Server) pag(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		s.mu.Lock()
+		live := ok && s.pagTokens[token]
+		s.mu.Unlock()
+		if !live {
+			writeError(w, http.StatusUnauthorized, "token expired")
+			return
+		}
+		h(w, r)
+	}
+}
+
+// visible reports whether id is a leaf customer the admin can see.
+func (s *Server) visible(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, customer := range s.customers {
+		if customer.ID == id && customer.Leaf {
+			return true
+		}
+	}
+	return false
+}
+
+// tenant returns the customer a request acts for: the one it is scoped to with customerId or
+// a {customer} path value, or else the customer the admin belongs to. PAG tokens belong to an
+// application of that customer too. Callers must hold s.mu.
+func (s *Server) tenant(r *http.Request) string {
+	if id := r.URL.Query().Get("customerId"); id != "" {
+		return id
+	}
+	if id := r.PathValue("customer"); id != "" {
+		return id
+	}
+	return s.defaultTenant()
+}
+
+// defaultTenant returns the customer the admin belongs to. Callers must hold s.mu.
+func (s *Server) defaultTenant() string {
+	for _, customer := range s.customers {
+		if customer.Leaf {
+			return customer.ID
+		}
+	}
+	return CustomerID
+}
@@ -0,0 +1,152 @@
+// Copyright 2025, Jamf Software LLC.
+package fakejsc
+
+import (
+	"html/template"
+	"net/http"
+	"regexp"
+)
+
+// Jamf ID (Auth0) login accepted by a Server. JamfIDUsername can not log in with a local
+// password, so the client falls back to the Jamf ID flow, which logs in with Password. The +
+// in JamfIDUsername checks that the client escapes it in the login-methods query.
+const (
+	JamfIDUsername     = "jamf-id+admin@example.com"
+	JamfIDRegistration = "jamf-auth0-eu"
+	JamfIDConnection   = "jamf-id-db"
+)
+
+// JamfIDAuthorizationPath is the authorization URL advertised in login-methods for JamfIDUsername.
+const JamfIDAuthorizationPath = "/oauth2/authorization/" + JamfIDRegistration + "?connection=" + JamfIDConnection
+
+// loginPage is a trimmed down Auth0 Universal Login page.
+var loginPage = template.Must(template.New("login").Parse(`<html><body>
+<form method="POST">
+<input type="hidden" name="state" value="{{.State}}">
+{{range .Fields}}<input type="text" name="{{.}}" value="">
+{{end}}<button type="submit" name="action" value="default">Continue</button>
+{{if .Error}}<span id="error-element-{{.Page}}" class="ulp-input-error-message">{{.Error}}</span>{{end}}
+</form>
+</body></html>`))
+
+var sixDigits = regexp.MustCompile(`^[0-9]{6}$`)
+
+// RequireJamfIDChallenge makes the Jamf ID flow stop at the Universal Login page path, such
+// as /u/mfa-otp-challenge, after the password. The OTP challenge accepts any six digit code,
+// or only the current one once RequireTOTP is set; other pages can not be completed.
+func (s *Server) RequireJamfIDChallenge(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jamfIDChallenge = path
+}
+
+// registerJamfID serves the OAuth authorization redirect and the Universal Login pages on
+// the same host as RADAR.
+func (s *Server) registerJamfID(mux *http.ServeMux) {
+	mux.HandleFunc("GET /oauth2/authorization/{registration}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("registration") != JamfIDRegistration || r.URL.Query().Get("connection") != JamfIDConnection {
+			writeError(w, http.StatusNotFound, "unknown authorization registration")
+			return
+		}
+		s.mu.Lock()
+		state := s.token("state")
+		s.jamfIDStates[state] = true
+		s.mu.Unlock()
+		http.Redirect(w, r, "/u/login/identifier?state="+state, http.StatusFound)
+	})
+
+	mux.HandleFunc("GET /u/login/identifier", s.loginPage("identifier", "username"))
+	mux.HandleFunc("POST /u/login/identifier", s.loginForm(func(w http.ResponseWriter, r *http.Request, state string) {
+		if r.PostForm.Get("username") != JamfIDUsername {
+			renderLoginPage(w, http.StatusBadRequest, "identifier", state, "Enter a valid email address", "username")
+			return
+		}
+		http.Redirect(w, r, "/u/login/password?state="+state, http.StatusFound)
+	}))
+
+	mux.HandleFunc("GET /u/login/password", s.loginPage("password", "password"))
+	mux.HandleFunc("POST /u/login/password", s.loginForm(func(w http.ResponseWriter, r *http.Request, state string) {
+		if r.PostForm.Get("password") != Password {
+			renderLoginPage(w, http.StatusBadRequest, "password", state, "Wrong email or password", "password")
+			return
+		}
+		s.mu.Lock()
+		challenge := s.jamfIDChallenge
+		s.mu.Unlock()
+		if challenge != "" {
+			http.Redirect(w, r, challenge+"?state="+state, http.StatusFound)
+			return
+		}
+		s.completeJamfID(w, r, state)
+	}))
+
+	mux.HandleFunc("GET /u/{page...}", s.loginPage("code", "code"))
+	mux.HandleFunc("POST /u/mfa-otp-challenge", s.loginForm(func(w http.ResponseWriter, r *http.Request, state string) {
+		s.mu.Lock()
+		secret := s.totpSecret
+		s.mu.Unlock()
+		code := r.PostForm.Get("code")
+		if !sixDigits.MatchString(code) || secret != "" && !validTOTP(secret, code) {
+			renderLoginPage(w, http.StatusBadRequest, "code", state, "The code you entered is invalid", "code")
+			return
+		}
+		s.completeJamfID(w, r, state)
+	}))
+
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>RADAR</body></html>"))
+	})
+}
+
+// loginPage renders a Universal Login page asking for fields.
+func (s *Server) loginPage(page string, fields ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		renderLoginPage(w, http.StatusOK, page, r.URL.Query().Get("state"), "", fields...)
+	}
+}
+
+// loginForm parses a Universal Login form submission and checks its state.
+func (s *Server) loginForm(h func(w http.ResponseWriter, r *http.Request, state string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		state := r.PostForm.Get("state")
+		s.mu.Lock()
+		valid := s.jamfIDStates[state]
+		s.mu.Unlock()
+		if !valid {
+			writeError(w, http.StatusBadRequest, "invalid state")
+			return
+		}
+		h(w, r, state)
+	}
+}
+
+// completeJamfID ends the login with a RADAR session and returns the browser to RADAR.
+func (s *Server) completeJamfID(w http.ResponseWriter, r *http.Request, state string) {
+	s.mu.Lock()
+	delete(s.jamfIDStates, state)
+	session := s.token("session")
+	s.sessions[session] = true
+	xsrf := s.token("xsrf")
+	s.xsrf[xsrf] = true
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{Name: "SESSION", Value: session, Path: "/", HttpOnly: true})
+	http.SetCookie(w, &http.Cookie{Name: "XSRF-TOKEN", Value: xsrf, Path: "/"})
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+func renderLoginPage(w http.ResponseWriter, status int, page, state, message string, fields ...string) {
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(status)
+	loginPage.Execute(w, struct {
+		Page   string
+		State  string
+		Fields []string
+		Error  string
+	}{page, state, fields, message})
+}
@@ -0,0 +1,22 @@
+// Copyright 2025, Jamf Software LLC.
+package fakejsc
+
+import "net/http"
+
+// registerPAG serves the Risk API gateway endpoints used by the pag_* resources.
+func (s *Server) registerPAG(mux *http.ServeMux) {
+	handle := func(pattern string, h http.HandlerFunc) {
+		mux.HandleFunc(pattern, s.pag(h))
+	}
+
+	handle("GET /ztna/v1/app-templates", list(appTemplates))
+	handle("GET /ztna/v1/vpn-routes", list(pagVPNRoutes))
+
+	apps := s.collections[PAGApps]
+	handle("GET /ztna/v1/apps", s.list(apps))
+	handle("POST /ztna/v1/apps", s.create(apps, "id"))
+	handle("GET /ztna/v1/apps/{id}", s.get(apps))
+	handle("PUT /ztna/v1/apps/{id}", s.replace(apps, "id"))
+	handle("PATCH /ztna/v1/apps/{id}", s.replace(apps, "id"))
+	handle("DELETE /ztna/v1/apps/{id}", s.remove(apps))
+}
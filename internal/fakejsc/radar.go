@@ -0,0 +1,373 @@
+// Copyright 2025, Jamf Software LLC.
+package fakejsc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// registerRadar serves the RADAR /gate services used by the provider.
+func (s *Server) registerRadar(mux *http.ServeMux) {
+	handle := func(pattern string, h http.HandlerFunc) {
+		method, path, _ := strings.Cut(pattern, " ")
+		mux.HandleFunc(method+" /gate"+path, s.radar(h))
+	}
+
+	// user-service
+	handle("GET /user-service/customer/v2/customers/visible-for-admin", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		customers := []object{}
+		for _, customer := range s.customers {
+			customers = append(customers, object{
+				"customerId": customer.ID,
+				"name":       customer.Name,
+				"leaf":       customer.Leaf,
+				"parentId":   customer.ParentID,
+			})
+		}
+		writeJSON(w, http.StatusOK, customers)
+	})
+	handle("GET /user-service/user/v3/{customer}/groups", list(groups))
+
+	// content-block-service
+	handle("GET /content-block-service/v1/customers/{customer}/categories", list(categories))
+
+	// identity-service
+	connections := s.collections[Connections]
+	handle("GET /identity-service/v1/connections", s.list(connections))
+	handle("POST /identity-service/v1/connections", func(w http.ResponseWriter, r *http.Request) {
+		var connection object
+		if !readJSON(w, r, &connection) {
+			return
+		}
+		// Entra connections wait for Microsoft consent, the rest are usable straight away
+		connection["state"] = "APPROVED"
+		if connection["type"] == "AZURE_END_USER" {
+			connection["state"] = "INITIAL"
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusCreated, connections.add(s.tenant(r), connection, "id"))
+	})
+	handle("DELETE /identity-service/v1/connections/{id}", s.remove(connections))
+	handle("POST /identity-service/v1/connections/{id}/consent-transactions", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := connections.get(s.tenant(r), r.PathValue("id")); !ok {
+			writeError(w, http.StatusNotFound, "connection not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, object{
+			"consentUrl": "https://login.microsoftonline.com/common/adminconsent?state=" + r.PathValue("id"),
+		})
+	})
+
+	// traffic-routing-service
+	apps := s.collections[Apps]
+	handle("GET /traffic-routing-service/v1/apps", s.list(apps))
+	handle("POST /traffic-routing-service/v1/apps", validateHostnames(s.create(apps, "id")))
+	handle("GET /traffic-routing-service/v1/apps/{id}", s.get(apps))
+	handle("PUT /traffic-routing-service/v1/apps/{id}", validateHostnames(s.replace(apps, "id")))
+	handle("DELETE /traffic-routing-service/v1/apps/{id}", s.remove(apps))
+	handle("GET /traffic-routing-service/v1/app-templates", list(appTemplates))
+	handle("GET /traffic-routing-service/v1/virtual-vpn-routes", list(groupedGateways))
+	handle("GET /traffic-routing-service/v2/vpn-routes", list(routes))
+
+	// dns-zone-management-service stores every mapping in a single document
+	handle("GET /dns-zone-management-service/v1/custom-hostname-mappings", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusOK, object{"mappings": s.hostnameMappings})
+	})
+	handle("PUT /dns-zone-management-service/v1/custom-hostname-mappings", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Mappings []object `json:"mappings"`
+		}
+		if !readJSON(w, r, &body) {
+			return
+		}
+		if body.Mappings == nil {
+			body.Mappings = []object{}
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.hostnameMappings = body.Mappings
+		writeJSON(w, http.StatusOK, object{"mappings": s.hostnameMappings})
+	})
+
+	// activation-profile-service
+	links := s.collections[EnrollmentLinks]
+	handle("GET /activation-profile-service/v1/enrollment-links", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		summaries := []object{}
+		for _, link := range links.list(s.tenant(r)) {
+			summaries = append(summaries, object{"code": link["code"], "name": link["name"]})
+		}
+		writeJSON(w, http.StatusOK, object{"links": summaries})
+	})
+	handle("POST /activation-profile-service/v2/enrollment-links", func(w http.ResponseWriter, r *http.Request) {
+		var link object
+		if !readJSON(w, r, &link) {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		link = links.add(s.tenant(r), link, "code")
+		writeJSON(w, http.StatusCreated, object{"code": link["code"]})
+	})
+	handle("GET /activation-profile-service/v1/enrollment-links/{id}", s.get(links))
+	handle("PUT /activation-profile-service/v1/enrollment-links/{id}", s.replace(links, "code"))
+	handle("DELETE /activation-profile-service/v1/enrollment-links/{id}", s.remove(links))
+
+	// uem-deployment-template-service renders the managed app configuration for a profile
+	handle("GET /uem-deployment-template-service/v1/activation-profiles/{id}/uems/JAMF/platforms/{platform}/types/{type}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		_, ok := links.get(s.tenant(r), r.PathValue("id"))
+		s.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusNotFound, "activation profile not found")
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprintf(w, "<!-- %s %s configuration for %s -->", r.PathValue("platform"), r.PathValue("type"), r.PathValue("id"))
+	})
+
+	// admin-service
+	admins := s.collections[Admins]
+	handle("GET /admin-service/v4/customers/{customer}/admins", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		data := admins.list(s.tenant(r))
+		writeJSON(w, http.StatusOK, object{"page": 0, "pageSize": 100, "totalCount": len(data), "data": data})
+	})
+	handle("POST /admin-service/v4/customers/{customer}/admins", func(w http.ResponseWriter, r *http.Request) {
+		var admin object
+		if !readJSON(w, r, &admin) {
+			return
+		}
+		admin["entityType"] = "CUSTOMER"
+		admin["entityId"] = r.PathValue("customer")
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		admins.add(s.tenant(r), admin, "id")
+		// The real API answers with an empty body, the new admin is found by listing
+		w.WriteHeader(http.StatusCreated)
+	})
+	handle("PUT /admin-service/v4/customers/{customer}/admins/{id}", s.replace(admins, "id"))
+	handle("DELETE /admin-service/v4/customers/{customer}/admins/{id}", s.remove(admins))
+
+	// block-service stores every block page type in a single document
+	handle("GET /block-service/blocks/v1/customers/{customer}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusOK, s.blockPages)
+	})
+	handle("PATCH /block-service/blocks/v1/customers/{customer}", func(w http.ResponseWriter, r *http.Request) {
+		var patch object
+		if !readJSON(w, r, &patch) {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for k, v := range patch {
+			s.blockPages[k] = v
+		}
+		writeJSON(w, http.StatusOK, s.blockPages)
+	})
+
+	// secure-policy-service
+	handle("GET /secure-policy-service/v1/secure-policies/customers/{customer}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusOK, s.securePolicy)
+	})
+	handle("PUT /secure-policy-service/v1/secure-policies/customers/{customer}", func(w http.ResponseWriter, r *http.Request) {
+		var policy object
+		if !readJSON(w, r, &policy) {
+			return
+		}
+		threats, ok := policy["threatCategories"].([]interface{})
+		if !ok {
+			writeError(w, http.StatusBadRequest, "threatCategories is required")
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		var fieldErrors []object
+		for i, threat := range threats {
+			t, _ := threat.(map[string]interface{})
+			id, _ := t["threatCategoryId"].(string)
+			if s.lockedThreats[id] && threatSeverity(threat) != s.threatSeverity(id) {
+				fieldErrors = append(fieldErrors, object{
+					"field":          fmt.Sprintf("threatCategories[%d]", i),
+					"defaultMessage": fmt.Sprintf("severity of %s cannot be changed", id),
+				})
+			}
+		}
+		if len(fieldErrors) > 0 {
+			writeValidationError(w, fieldErrors...)
+			return
+		}
+		s.securePolicy = policy
+		writeJSON(w, http.StatusOK, s.securePolicy)
+	})
+
+	// connector-service
+	configs := s.collections[UEMConfigs]
+	handle("GET /connector-service/v2/config", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusOK, object{"configs": configs.list(s.tenant(r))})
+	})
+	handle("POST /connector-service/v2/config/emm-server", s.create(configs, "id"))
+	handle("DELETE /connector-service/v2/config/{id}", s.remove(configs))
+
+	// physical-access-service allows a single integration per customer
+	integrations := s.collections[Integrations]
+	handle("GET /physical-access-service/v1/integrations/{customer}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, integration := range integrations.list(s.tenant(r)) {
+			if integration["customerId"] == r.PathValue("customer") {
+				writeJSON(w, http.StatusOK, integration)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "no integration for customer")
+	})
+	handle("POST /physical-access-service/v1/integrations/{customer}", func(w http.ResponseWriter, r *http.Request) {
+		var integration object
+		if !readJSON(w, r, &integration) {
+			return
+		}
+		integration["customerId"] = r.PathValue("customer")
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, existing := range integrations.list(s.tenant(r)) {
+			if existing["customerId"] == r.PathValue("customer") {
+				writeError(w, http.StatusConflict, "customer already has an integration")
+				return
+			}
+		}
+		writeJSON(w, http.StatusCreated, integrations.add(s.tenant(r), integration, "id"))
+	})
+	handle("DELETE /physical-access-service/v2/integrations/{id}", s.remove(integrations))
+}
+
+// list serves a fixed list of objects.
+func list(objs []object) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, objs)
+	}
+}
+
+var hostnamePattern = regexp.MustCompile(`^(\*\.)?[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?)*$`)
+
+// validateHostnames rejects an app whose hostnames are not valid, naming each bad one by its
+// index, before passing the request on to next.
+func validateHostnames(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var app struct {
+			Hostnames []string `json:"hostnames"`
+		}
+		json.Unmarshal(body, &app)
+		var fieldErrors []object
+		for i, hostname := range app.Hostnames {
+			if !hostnamePattern.MatchString(hostname) {
+				fieldErrors = append(fieldErrors, object{
+					"field":          fmt.Sprintf("hostnames[%d]", i),
+					"rejectedValue":  hostname,
+					"defaultMessage": fmt.Sprintf("%q is not a valid hostname", hostname),
+				})
+			}
+		}
+		if len(fieldErrors) > 0 {
+			writeValidationError(w, fieldErrors...)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// list serves every object in c as a bare JSON array.
+func (s *Server) list(c *collection) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusOK, c.list(s.tenant(r)))
+	}
+}
+
+// create stores the request body in c and returns it with its new ID in idField.
+func (s *Server) create(c *collection, idField string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var obj object
+		if !readJSON(w, r, &obj) {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusCreated, c.add(s.tenant(r), obj, idField))
+	}
+}
+
+// get serves the object in c named by the {id} path value.
+func (s *Server) get(c *collection) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		obj, ok := c.get(s.tenant(r), r.PathValue("id"))
+		if !ok {
+			writeError(w, http.StatusNotFound, r.PathValue("id")+" not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, obj)
+	}
+}
+
+// replace merges the request body onto the object in c named by the {id} path value. The
+// ID in idField cannot be changed.
+func (s *Server) replace(c *collection, idField string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var patch object
+		if !readJSON(w, r, &patch) {
+			return
+		}
+		delete(patch, idField)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		obj, ok := c.merge(s.tenant(r), r.PathValue("id"), patch)
+		if !ok {
+			writeError(w, http.StatusNotFound, r.PathValue("id")+" not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, obj)
+	}
+}
+
+// remove deletes the object in c named by the {id} path value.
+func (s *Server) remove(c *collection) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !c.remove(s.tenant(r), r.PathValue("id")) {
+			writeError(w, http.StatusNotFound, r.PathValue("id")+" not found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2025, Jamf Software LLC.
+package fakejsc
+
+// defaultLogo is the logo every block page starts with.
+const defaultLogo = "iVBORw0KGgoAAAANSUhEUgAAAC0AAAAtCAMAAAANxBKo"
+
+// defaultBlockPages returns the block page settings of a new tenant.
+func defaultBlockPages() object {
+	pages := object{
+		"jamfCustomizableBlockSupport": false,
+		"privateRelayDomainsBlock":     false,
+	}
+	for _, pageType := range []string{"block", "secureBlock", "cap", "deviceRisk", "deviceManagement"} {
+		pages[pageType] = object{
+			"description":        "The site you are attempting to view has been blocked. If you would like more information please contact your administrator.",
+			"enabled":            false,
+			"logo":               defaultLogo,
+			"logoType":           "image/png",
+			"showClassification": true,
+			"showRequestUrl":     true,
+			"showTransactionId":  true,
+			"templateId":         "default",
+			"title":              "Site Blocked",
+		}
+	}
+	return pages
+}
+
+// defaultSeverities are the threat severities of a new tenant.
+var defaultSeverities = map[string]string{
+	"ACCESS_PHISHING_HOST":                               "HIGHEST",
+	"APP_LEAK_CREDIT_CARD":                               "HIGH",
+	"APP_LEAK_PASSWORD":                                  "MEDIUM",
+	"APP_LEAK_EMAIL":                                     "LOW",
+	"APP_LEAK_USERID":                                    "LOW",
+	"APP_LEAK_LOCATION":                                  "LOW",
+	"RESOURCE_LEAK_CREDIT_CARD":                          "HIGH",
+	"RESOURCE_LEAK_PASSWORD":                             "MEDIUM",
+	"RESOURCE_LEAK_EMAIL":                                "LOW",
+	"RESOURCE_LEAK_USERID":                               "LOW",
+	"RESOURCE_LEAK_LOCATION":                             "LOW",
+	"ACCESS_BAD_HOST":                                    "HIGH",
+	"ACCESS_CRYPTOJACKING_HOST":                          "MEDIUM",
+	"ACCESS_SPAM_HOST":                                   "MEDIUM",
+	"RISKY_APP_DOWNLOAD":                                 "LOW",
+	"APP_MALICIOUS_APP_IN_INVENTORY":                     "HIGHEST",
+	"APP_SPYWARE_APP_IN_INVENTORY":                       "HIGHEST",
+	"APP_TROJAN_MALWARE_APP_IN_INVENTORY":                "HIGHEST",
+	"APP_RANSOMWARE_APP_IN_INVENTORY":                    "HIGHEST",
+	"APP_BANKER_MALWARE_APP_IN_INVENTORY":                "HIGHEST",
+	"APP_SMS_MALWARE_APP_IN_INVENTORY":                   "HIGHEST",
+	"APP_ADWARE_APP_IN_INVENTORY":                        "HIGHEST",
+	"APP_ROOTING_MALWARE_APP_IN_INVENTORY":               "HIGHEST",
+	"APP_POTENTIALLY_UNWANTED_APP_IN_INVENTORY":          "MEDIUM",
+	"APP_ADMIN_APP_IN_INVENTORY":                         "MEDIUM",
+	"APP_SIDE_LOADED_APP_IN_INVENTORY":                   "MEDIUM",
+	"APP_THIRD_PARTY_APP_STORES_IN_INVENTORY":            "LOW",
+	"APP_VULNERABLE_APP_IN_INVENTORY":                    "LOW",
+	"CERTIFICATE_SSL_TRUST_COMPROMISE":                   "HIGHEST",
+	"NETWORK_ACCESS_POINT_SSL_MITM_TRUSTED_VALID_CERT":   "HIGHEST",
+	"NETWORK_ACCESS_POINT_SSL_MITM_UNTRUSTED_VALID_CERT": "HIGH",
+	"NETWORK_ACCESS_POINT_SSL_STRIP_MITM":                "HIGHEST",
+	"RISKY_HOTSPOT":                                      "MEDIUM",
+	"OS_JAILBREAK":                                       "HIGHEST",
+	"OS_OUTDATED_OS":                                     "HIGH",
+	"OS_OUTDATED_OS_LOW":                                 "MEDIUM",
+	"OS_OUT_OF_DATE_OS":                                  "LOW",
+	"DEVICE_APP_INACTIVITY":                              "MEDIUM",
+	"DEVICE_STORAGE_ENCRYPTION_DISABLED":                 "MEDIUM",
+	"DEVICE_LOCK_SCREEN_DISABLED":                        "MEDIUM",
+	"IOS_PROFILE":                                        "MEDIUM",
+	"DEVICE_MISSING_ANDROID_SECURITY_PATCHES":            "LOW",
+	"DEVICE_UNKNOWN_SOURCES_ENABLED":                     "LOW",
+	"DEVICE_USB_APP_VERIFICATION_DISABLED":               "LOW",
+	"DEVICE_USER_PASSWORD_DISABLED":                      "LOW",
+	"DEVICE_DEVELOPER_MODE_ENABLED":                      "LOWEST",
+	"DEVICE_USB_DEBUGGING_ENABLED":                       "LOWEST",
+	"DEVICE_ANTIVIRUS_DISABLED":                          "MEDIUM",
+	"DEVICE_FIREWALL_DISABLED":                           "MEDIUM",
+}
+
+// defaultSecurePolicy returns the secure policy of a new tenant.
+func defaultSecurePolicy() object {
+	threats := []interface{}{}
+	for id, severity := range defaultSeverities {
+		threats = append(threats, object{
+			"threatCategoryId": id,
+			"action": object{
+				"response":           "NONE",
+				"notificationPolicy": object{"enabled": false},
+				"reportingPolicy": object{
+					"types":             []interface{}{"PORTAL"},
+					"deviceDelay":       "NONE",
+					"affectsDeviceRisk": true,
+					"severity":          severity,
+				},
+				"analysisPolicy": object{},
+			},
+		})
+	}
+	return object{
+		"summaryNotificationPolicy": object{"enabled": false},
+		"customerConfiguration":     object{},
+		"threatCategories":          threats,
+		"groupPolicyOverrides":      []interface{}{},
+	}
+}
+
+// Read-only data returned by the list endpoints the provider only has data sources for.
+var (
+	routes = []object{
+		{"id": "route-1", "name": "Fake Route London", "shared": true, "deployments": []object{{"datacenter": "lon"}}},
+		{"id": "route-2", "name": "Fake Route Frankfurt", "shared": true, "deployments": []object{{"datacenter": "fra"}}},
+	}
+	groupedGateways = []object{
+		{"id": "ggw-1", "name": "Fake Grouped Gateway", "shared": false, "customerIds": []string{CustomerID}, "routeIds": []string{"route-1", "route-2"}, "recoveryDelayInSec": 60, "routingStrategy": "LATENCY"},
+	}
+	appTemplates = []object{
+		{"id": "template-1", "name": "Fake Template", "hostnames": []string{"fake.example.com", "*.fake.example.com"}},
+	}
+	pagVPNRoutes = []object{
+		{"id": "pag-route-1", "name": "Fake PAG Route", "shared": true, "customerIds": []string{}},
+	}
+	categories = []object{
+		{"id": "category-1", "name": "SOCIAL_MEDIA", "displayName": "Social Media"},
+		{"id": "category-2", "name": "GAMBLING", "displayName": "Gambling"},
+	}
+	groups = []object{
+		{"group": "Fake Group", "groupId": "group-1", "devices": 3, "deletedDevices": 0},
+		{"group": nil, "groupId": nil, "devices": 1, "deletedDevices": 0},
+	}
+)
@@ -0,0 +1,465 @@
+// Copyright 2025, Jamf Software LLC.
+
+// Package fakejsc is an in-memory stand-in for the RADAR and PAG APIs used by the provider.
+// It speaks just enough of each service for the resources and data sources to be created,
+// read, updated, imported and destroyed against it, so acceptance tests can run without a
+// real tenant.
+package fakejsc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Default credentials accepted by a Server.
+const (
+	Username          = "admin@example.com"
+	Password          = "password"
+	ApplicationID     = "fake-application-id"
+	ApplicationSecret = "fake-application-secret"
+	CustomerID        = "00000000-0000-0000-0000-000000000001"
+	AdminID           = "00000000-0000-0000-0000-0000000000a1"
+)
+
+// Collection names accepted by Add, Has, SetField and Remove.
+const (
+	Connections     = "connections"
+	Apps            = "apps"
+	EnrollmentLinks = "enrollment-links"
+	Admins          = "admins"
+	UEMConfigs      = "uem-configs"
+	Integrations    = "integrations"
+	PAGApps         = "pag-apps"
+)
+
+// Customer is a customer returned from visible-for-admin.
+type Customer struct {
+	ID       string
+	Name     string
+	Leaf     bool
+	ParentID string
+}
+
+// Server is a running fake JSC API. The provider reaches it by setting both domain_name
+// and pag_domain_name to URL.
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+
+	// Customers visible to the admin. The first leaf customer is the one the admin belongs to.
+	customers []Customer
+
+	sessions  map[string]bool
+	xsrf      map[string]bool
+	pagTokens map[string]bool
+	nextToken int
+
+	pagTokenLifetime time.Duration // time from a PAG login to the exp claim of its token
+
+	jamfIDStates    map[string]bool // Jamf ID logins in progress
+	jamfIDChallenge string          // page shown after the Jamf ID password, if any
+
+	totpSecret     string // base32 secret logins must answer with a code, if MFA is required
+	backupCodeUsed bool
+
+	collections map[string]*collection
+
+	hostnameMappings []object
+	blockPages       object
+	securePolicy     object
+	lockedThreats    map[string]bool // threat categories whose severity cannot be changed
+
+	failures []failure
+	holds    map[string]chan struct{} // path prefixes whose requests wait until released
+	requests []string
+}
+
+type object = map[string]interface{}
+
+// failure is a queued error response for requests whose path starts with prefix.
+type failure struct {
+	prefix string
+	status int
+}
+
+// New starts a Server with a single leaf customer and a small amount of seeded read-only data.
+// The server is closed when the test finishes.
+func New(t testing.TB) *Server {
+	s := &Server{
+		customers: []Customer{{ID: CustomerID, Name: "Fake Customer", Leaf: true}},
+		sessions:  map[string]bool{},
+		xsrf:      map[string]bool{},
+		pagTokens: map[string]bool{},
+
+		pagTokenLifetime: time.Hour,
+		jamfIDStates:     map[string]bool{},
+		collections: map[string]*collection{
+			Connections:     newCollection("connection"),
+			Apps:            newCollection("app"),
+			EnrollmentLinks: newCollection("ap"),
+			Admins:          newCollection("admin"),
+			UEMConfigs:      newCollection("uemc"),
+			Integrations:    newCollection("integration"),
+			PAGApps:         newCollection("pag-app"),
+		},
+		hostnameMappings: []object{},
+		blockPages:       defaultBlockPages(),
+		securePolicy:     defaultSecurePolicy(),
+		lockedThreats:    map[string]bool{},
+		holds:            map[string]chan struct{}{},
+	}
+
+	mux := http.NewServeMux()
+	s.registerAuth(mux)
+	s.registerJamfID(mux)
+	s.registerRadar(mux)
+	s.registerPAG(mux)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "no handler for "+r.Method+" "+r.URL.Path)
+	})
+
+	s.Server = httptest.NewServer(s.record(mux))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// SetCustomers replaces the customers visible to the admin. When the list contains a
+// non-leaf customer the admin is treated as a parent (MSP) admin of that customer.
+func (s *Server) SetCustomers(customers ...Customer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.customers = customers
+}
+
+// Add stores obj in the named collection for the customer the admin belongs to, as if it was
+// created in the portal, and returns its new ID.
+func (s *Server) Add(name string, obj map[string]interface{}) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj = s.collections[name].add(s.defaultTenant(), maps.Clone(obj), "id")
+	return obj["id"].(string)
+}
+
+// Has reports whether the named collection holds an object with the given ID, for any customer.
+func (s *Server) Has(name, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.collections[name].items[id]
+	return ok
+}
+
+// SetField changes a top level field of an object behind the provider's back, as if it was
+// edited in the portal.
+func (s *Server) SetField(name, id, field string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collections[name]
+	c.merge(c.owners[id], id, object{field: value})
+}
+
+// Remove deletes an object behind the provider's back, as if it was removed in the portal.
+func (s *Server) Remove(name, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collections[name]
+	c.remove(c.owners[id], id)
+}
+
+// HostnameMappings returns the hostnames of all custom hostname mappings.
+func (s *Server) HostnameMappings() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hostnames := []string{}
+	for _, mapping := range s.hostnameMappings {
+		hostnames = append(hostnames, fmt.Sprint(mapping["hostname"]))
+	}
+	return hostnames
+}
+
+// SetBlockPage changes a setting of a block page type behind the provider's back, as if it
+// was edited in the portal.
+func (s *Server) SetBlockPage(pageType, key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	page, _ := s.blockPages[pageType].(map[string]interface{})
+	if page == nil {
+		page = object{}
+	}
+	page[key] = value
+	s.blockPages[pageType] = page
+}
+
+// BlockPage returns the settings of a block page type.
+func (s *Server) BlockPage(pageType string) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	page, _ := s.blockPages[pageType].(map[string]interface{})
+	return maps.Clone(page)
+}
+
+// BlockPageFlag returns a customer wide flag of the block page document, such as
+// jamfCustomizableBlockSupport.
+func (s *Server) BlockPageFlag(name string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.blockPages[name]
+}
+
+// ThreatSeverity returns the reporting severity of a threat category in the secure policy.
+func (s *Server) ThreatSeverity(id string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.threatSeverity(id)
+}
+
+func (s *Server) threatSeverity(id string) string {
+	threats, _ := s.securePolicy["threatCategories"].([]interface{})
+	for _, threat := range threats {
+		if t, _ := threat.(map[string]interface{}); t["threatCategoryId"] == id {
+			return threatSeverity(t)
+		}
+	}
+	return ""
+}
+
+// threatSeverity returns the reporting severity of a threatCategories entry.
+func threatSeverity(threat interface{}) string {
+	t, _ := threat.(map[string]interface{})
+	action, _ := t["action"].(map[string]interface{})
+	reportingPolicy, _ := action["reportingPolicy"].(map[string]interface{})
+	severity, _ := reportingPolicy["severity"].(string)
+	return severity
+}
+
+// LockThreat makes the secure policy PUT reject a change to the severity of threat category
+// id with a field error naming the category by its index in threatCategories.
+func (s *Server) LockThreat(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lockedThreats[id] = true
+}
+
+// ExpireSessions invalidates every RADAR session and PAG token, forcing the client to log in again.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = map[string]bool{}
+	s.pagTokens = map[string]bool{}
+}
+
+// Fail queues times error responses with status for requests whose path starts with prefix.
+func (s *Server) Fail(prefix string, status int, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < times; i++ {
+		s.failures = append(s.failures, failure{prefix: prefix, status: status})
+	}
+}
+
+// SetPAGTokenLifetime sets how long PAG tokens issued from now on are valid for, according to
+// their exp claim.
+func (s *Server) SetPAGTokenLifetime(lifetime time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pagTokenLifetime = lifetime
+}
+
+// Hold makes requests whose path starts with prefix wait, without an answer, until release is
+// called or the client gives up on them.
+func (s *Server) Hold(prefix string) (release func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	held := make(chan struct{})
+	s.holds[prefix] = held
+	return sync.OnceFunc(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.holds, prefix)
+		close(held)
+	})
+}
+
+// Requests returns every request received so far as "METHOD path?query".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// record logs each request, numbers it with an X-Request-Id, waits out any hold and serves any
+// queued failure before passing it on.
+func (s *Server) record(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+		w.Header().Set("X-Request-Id", fmt.Sprintf("request-%d", len(s.requests)))
+		status := 0
+		for i, f := range s.failures {
+			if strings.HasPrefix(r.URL.Path, f.prefix) {
+				status = f.status
+				s.failures = append(s.failures[:i], s.failures[i+1:]...)
+				break
+			}
+		}
+		var held chan struct{}
+		for prefix, h := range s.holds {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				held = h
+			}
+		}
+		s.mu.Unlock()
+
+		if held != nil {
+			select {
+			case <-held:
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if status != 0 {
+			writeError(w, status, "injected failure")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// token returns a new opaque token with the given prefix. Callers must hold s.mu.
+func (s *Server) token(prefix string) string {
+	s.nextToken++
+	return fmt.Sprintf("%s-%d", prefix, s.nextToken)
+}
+
+// jwt returns a new unsigned JWT whose exp claim is the PAG token lifetime away, an hour unless
+// set with SetPAGTokenLifetime. Callers must hold s.mu.
+func (s *Server) jwt() string {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	claims, _ := json.Marshal(map[string]interface{}{
+		"sub": ApplicationID,
+		"jti": s.token("jwt"),
+		"exp": time.Now().Add(s.pagTokenLifetime).Unix(),
+	})
+	return header + "." + enc.EncodeToString(claims) + "."
+}
+
+// collection is an ordered set of JSON objects keyed by a generated ID. Each object belongs to
+// the customer it was created for and is not found when asked for under another one.
+type collection struct {
+	prefix string
+	next   int
+	order  []string
+	items  map[string]object
+	owners map[string]string // customer ID of each object
+}
+
+func newCollection(prefix string) *collection {
+	return &collection{prefix: prefix, items: map[string]object{}, owners: map[string]string{}}
+}
+
+// add stores obj for customer under a new ID, which is also written to obj[idField].
+func (c *collection) add(customer string, obj object, idField string) object {
+	c.next++
+	id := fmt.Sprintf("%s-%d", c.prefix, c.next)
+	obj[idField] = id
+	c.order = append(c.order, id)
+	c.items[id] = obj
+	c.owners[id] = customer
+	return obj
+}
+
+func (c *collection) get(customer, id string) (object, bool) {
+	obj, ok := c.items[id]
+	if !ok || c.owners[id] != customer {
+		return nil, false
+	}
+	return obj, true
+}
+
+// list returns the objects of customer in the order they were added.
+func (c *collection) list(customer string) []object {
+	objs := []object{}
+	for _, id := range c.order {
+		if c.owners[id] == customer {
+			objs = append(objs, c.items[id])
+		}
+	}
+	return objs
+}
+
+// merge copies the top level keys of patch onto the stored object.
+func (c *collection) merge(customer, id string, patch object) (object, bool) {
+	obj, ok := c.get(customer, id)
+	if !ok {
+		return nil, false
+	}
+	for k, v := range patch {
+		obj[k] = v
+	}
+	return obj, true
+}
+
+func (c *collection) remove(customer, id string) bool {
+	if _, ok := c.get(customer, id); !ok {
+		return false
+	}
+	delete(c.items, id)
+	delete(c.owners, id)
+	for i, v := range c.order {
+		if v == id {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error body in the shape returned by the JSC gateway.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, object{
+		"status":  status,
+		"error":   http.StatusText(status),
+		"message": message,
+	})
+}
+
+// writeValidationError writes a 400 listing field errors, in the shape of the binding errors
+// returned by the JSC services, with the request ID as the trace ID.
+func writeValidationError(w http.ResponseWriter, fieldErrors ...object) {
+	writeJSON(w, http.StatusBadRequest, object{
+		"status":  http.StatusBadRequest,
+		"error":   http.StatusText(http.StatusBadRequest),
+		"message": "Validation failed",
+		"traceId": w.Header().Get("X-Request-Id"),
+		"errors":  fieldErrors,
+	})
+}
+
+// readJSON decodes the request body into v, writing a 400 and returning false on failure.
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	body, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(body, v)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+// Copyright 2025, Jamf Software LLC.
+package fakejsc
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// BackupCode is the one-time backup code accepted instead of an authenticator code once
+// RequireTOTP is set.
+const BackupCode = "12345678"
+
+// RequireTOTP enables MFA for the local admin. /auth/v1/credentials then rejects a login
+// without the current authenticator code for the base32 secret, or the unused BackupCode, and
+// the Jamf ID OTP challenge checks the code against secret too.
+func (s *Server) RequireTOTP(secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.totpSecret = secret
+	s.backupCodeUsed = false
+}
+
+// validTOTP reports whether code is the authenticator code for secret in the current 30
+// second step or the one either side of it, as clocks drift.
+func validTOTP(secret, code string) bool {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil || code == "" {
+		return false
+	}
+	step := time.Now().Unix() / 30
+	for _, counter := range []int64{step - 1, step, step + 1} {
+		var msg [8]byte
+		binary.BigEndian.PutUint64(msg[:], uint64(counter))
+		mac := hmac.New(sha1.New, key)
+		mac.Write(msg[:])
+		sum := mac.Sum(nil)
+		offset := sum[len(sum)-1] & 0x0f
+		value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+		if fmt.Sprintf("%06d", value%1000000) == code {
+			return true
+		}
+	}
+	return false
+}
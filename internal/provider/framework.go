@@ -0,0 +1,76 @@
+// Copyright 2025, Jamf Software LLC.
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"jsctfprovider/endpoints/uemc"
+	"jsctfprovider/internal/auth"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	fwprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// frameworkProvider is the terraform-plugin-framework half of the jsc provider, served next to
+// the SDK provider by ProviderServer. A resource or data source moves over by adding it here
+// and removing it from New under the same type name, so existing state keeps working.
+type frameworkProvider struct {
+	version string
+	sdk     *schema.Provider // configured before this provider, see Configure
+}
+
+var _ fwprovider.Provider = (*frameworkProvider)(nil)
+
+func (p *frameworkProvider) Metadata(ctx context.Context, req fwprovider.MetadataRequest, resp *fwprovider.MetadataResponse) {
+	resp.TypeName = "jsc"
+	resp.Version = p.version
+}
+
+// Schema mirrors the SDK provider block, as the mux requires every provider to declare the
+// same one. It is derived from the SDK schema so the two can not drift apart.
+func (p *frameworkProvider) Schema(ctx context.Context, req fwprovider.SchemaRequest, resp *fwprovider.SchemaResponse) {
+	attributes := map[string]fwschema.Attribute{}
+	for name, s := range p.sdk.Schema {
+		switch s.Type {
+		case schema.TypeString:
+			attributes[name] = fwschema.StringAttribute{Required: s.Required, Optional: s.Optional, Sensitive: s.Sensitive, Description: s.Description, DeprecationMessage: s.Deprecated}
+		case schema.TypeInt:
+			attributes[name] = fwschema.Int64Attribute{Required: s.Required, Optional: s.Optional, Sensitive: s.Sensitive, Description: s.Description, DeprecationMessage: s.Deprecated}
+		case schema.TypeFloat:
+			attributes[name] = fwschema.Float64Attribute{Required: s.Required, Optional: s.Optional, Sensitive: s.Sensitive, Description: s.Description, DeprecationMessage: s.Deprecated}
+		case schema.TypeBool:
+			attributes[name] = fwschema.BoolAttribute{Required: s.Required, Optional: s.Optional, Sensitive: s.Sensitive, Description: s.Description, DeprecationMessage: s.Deprecated}
+		default:
+			resp.Diagnostics.AddError("Unsupported provider attribute", fmt.Sprintf("%s is a %s, which the framework provider schema does not mirror", name, s.Type))
+		}
+	}
+	resp.Schema = fwschema.Schema{Attributes: attributes}
+}
+
+// Configure hands framework resources the client of the SDK provider. The mux configures its
+// providers in order and the SDK one comes first, so both share one session and one set of
+// limits instead of logging in twice.
+func (p *frameworkProvider) Configure(ctx context.Context, req fwprovider.ConfigureRequest, resp *fwprovider.ConfigureResponse) {
+	client, ok := p.sdk.Meta().(*auth.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Provider not configured", "the jsc SDK provider must be configured before the framework provider")
+		return
+	}
+	resp.ResourceData = client
+	resp.DataSourceData = client
+	resp.EphemeralResourceData = client
+}
+
+func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		uemc.NewResource,
+	}
+}
+
+func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
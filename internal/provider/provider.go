@@ -0,0 +1,381 @@
+// Copyright 2025, Jamf Software LLC.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"jsctfprovider/endpoints/activationprofiles"
+	"jsctfprovider/endpoints/admin"
+	"jsctfprovider/endpoints/blockpages"
+	"jsctfprovider/endpoints/categories"
+	currentsession "jsctfprovider/endpoints/current_session"
+	"jsctfprovider/endpoints/customers"
+	entraidp "jsctfprovider/endpoints/entra_idp"
+	"jsctfprovider/endpoints/groupedgws"
+	"jsctfprovider/endpoints/groups"
+	"jsctfprovider/endpoints/hostnamemapping"
+	"jsctfprovider/endpoints/idp"
+	pagapptemplates "jsctfprovider/endpoints/pag_apptemplates"
+	pagvpnroutes "jsctfprovider/endpoints/pag_vpnroutes"
+	pagztnaapp "jsctfprovider/endpoints/pag_ztna_app"
+	physicalaccess "jsctfprovider/endpoints/physical_access"
+	"jsctfprovider/endpoints/routes"
+	"jsctfprovider/endpoints/securepolicy"
+	"jsctfprovider/endpoints/ztna"
+	ztnaapp "jsctfprovider/endpoints/ztna_app"
+	"jsctfprovider/internal/auth"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/tf5to6server"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ProviderServer returns the protocol 6 server for the provider, served by main and by the
+// acceptance tests. It muxes the SDK provider from New, upgraded from protocol 5, with the
+// terraform-plugin-framework provider, so resources can move to the framework one at a time.
+func ProviderServer(ctx context.Context, version string) (func() tfprotov6.ProviderServer, error) {
+	sdk := New(version)()
+	upgraded, err := tf5to6server.UpgradeServer(ctx, sdk.GRPCProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	// The SDK provider is listed first so it is configured first, see frameworkProvider.Configure
+	mux, err := tf6muxserver.NewMuxServer(ctx,
+		func() tfprotov6.ProviderServer { return upgraded },
+		providerserver.NewProtocol6(&frameworkProvider{version: version, sdk: sdk}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return mux.ProviderServer, nil
+}
+
+// New returns a factory for the SDK half of the jsc provider at the given version, which
+// ProviderServer muxes with the framework half.
+func New(version string) func() *schema.Provider {
+	return func() *schema.Provider {
+		p := &schema.Provider{
+			Schema: map[string]*schema.Schema{
+				"domain_name": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_DOMAIN", "radar.wandera.com"),
+					Description: "The JSC domain. May include a scheme, e.g. http://127.0.0.1:8080; defaults to https. Can also be set with JSC_DOMAIN.",
+				},
+				"username": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_USERNAME", nil),
+					Description: "The JSC username used for authentication. Must be local account - SSO or SAML not supported. Can also be set with JSC_USERNAME.",
+				},
+				"password": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_PASSWORD", nil),
+					Description: "The JSC password used for authentication. Can also be set with JSC_PASSWORD.",
+				},
+				"customerid": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_CUSTOMER_ID", "empty"),
+					Description: "The optional customerID. If not provided, the provider will attempt to discover. Can also be set with JSC_CUSTOMER_ID.",
+				},
+				"applicationid": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_APPLICATION_ID", nil),
+					Description: "The optional applicationid. Required for PAG resource types. Can also be set with JSC_APPLICATION_ID.",
+				},
+				"applicationsecret": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_APPLICATION_SECRET", nil),
+					Description: "The optional applicationsecret. Required for PAG resource types. Can also be set with JSC_APPLICATION_SECRET.",
+				},
+				"pag_domain_name": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_PAG_DOMAIN", auth.DefaultPAGDomainName),
+					Description: "The PAG (Risk API) gateway domain, for regional tenants or a local stand-in server. May include a scheme, e.g. http://127.0.0.1:8080; defaults to https. Can also be set with JSC_PAG_DOMAIN.",
+				},
+				"totp_secret": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_TOTP_SECRET", nil),
+					Description: "The optional base32 TOTP secret for accounts with MFA enforced. Codes are generated at login and on every re-authentication. Can also be set with JSC_TOTP_SECRET.",
+				},
+				"backup_code": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_BACKUP_CODE", nil),
+					Description: "An optional one-time MFA backup code. Only sent on the first login of a run. Can also be set with JSC_BACKUP_CODE.",
+				},
+				"max_retries": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("JSC_MAX_RETRIES", auth.DefaultRetryPolicy.MaxRetries),
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "How many times a failed request is retried. Timeouts, 408, 429 and 5xx responses are retried with jittered exponential backoff, honouring Retry-After on 429 and 503. 401 and 403 trigger a single re-authentication, which does not count as a retry. Requests that create objects are only resent when the server cannot have processed them. Can also be set with JSC_MAX_RETRIES.",
+				},
+				"retry_min_wait": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("JSC_RETRY_MIN_WAIT", int(auth.DefaultRetryPolicy.MinWait/time.Second)),
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Seconds to wait before the first retry. Doubles on every following retry. Can also be set with JSC_RETRY_MIN_WAIT.",
+				},
+				"retry_max_wait": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("JSC_RETRY_MAX_WAIT", int(auth.DefaultRetryPolicy.MaxWait/time.Second)),
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Upper bound in seconds for the wait between retries. Can also be set with JSC_RETRY_MAX_WAIT.",
+				},
+				"proxy_url": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_PROXY_URL", nil),
+					Description: "The optional proxy for every request, e.g. http://proxy.example.com:3128. When not set HTTPS_PROXY, HTTP_PROXY and NO_PROXY are honoured. Can also be set with JSC_PROXY_URL.",
+				},
+				"ca_cert_file": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_CA_CERT_FILE", nil),
+					Description: "Path to a PEM bundle of CA certificates to trust in addition to the system roots, e.g. for a TLS inspecting proxy. Can also be set with JSC_CA_CERT_FILE.",
+				},
+				"ca_cert_pem": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_CA_CERT_PEM", nil),
+					Description: "PEM encoded CA certificates to trust in addition to the system roots. Can be combined with ca_cert_file. Can also be set with JSC_CA_CERT_PEM.",
+				},
+				"insecure_skip_verify": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_INSECURE_SKIP_VERIFY", false),
+					Description: "Disables TLS certificate verification. Only intended for local test stand-ins. Can also be set with JSC_INSECURE_SKIP_VERIFY.",
+				},
+				"jamf_id_registration": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_JAMF_ID_REGISTRATION", nil),
+					Description: "The Jamf ID (Auth0) authorization registration used when the account can not log in locally, e.g. jamf-auth0-eu. Discovered from the login methods of the account when not set, falling back to jamf-auth0-us. Can also be set with JSC_JAMF_ID_REGISTRATION.",
+				},
+				"jamf_id_connection": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_JAMF_ID_CONNECTION", nil),
+					Description: "The Jamf ID (Auth0) connection used with jamf_id_registration. Defaults to the discovered connection or jamf-id-db. Can also be set with JSC_JAMF_ID_CONNECTION.",
+				},
+				"session_cache_dir": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_SESSION_CACHE_DIR", nil),
+					Description: "An optional directory to cache the login session in between runs, so plans reuse it instead of logging in again. The session is encrypted with the configured password and applicationsecret and checked with a single request before use. Can also be set with JSC_SESSION_CACHE_DIR.",
+				},
+				"audit_log_path": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_AUDIT_LOG_PATH", nil),
+					Description: "An optional file to append a JSON line to for every POST, PUT, PATCH and DELETE request, with the time, Terraform resource type and ID, method, path, status, request ID and a SHA-256 of the request body with secrets redacted. Can also be set with JSC_AUDIT_LOG_PATH.",
+				},
+				"read_only": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("JSC_READ_ONLY", false),
+					Description: "Refuses every request that could change the tenant, so data sources and refreshes work but creating, updating or deleting anything fails before a request is sent. Logins are still allowed. Can also be set with JSC_READ_ONLY.",
+				},
+				"max_concurrent_requests": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("JSC_MAX_CONCURRENT_REQUESTS", 0),
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "The most requests the provider has in flight at once, across all resources, whatever Terraform's parallelism. 0 means no limit. Can also be set with JSC_MAX_CONCURRENT_REQUESTS.",
+				},
+				"requests_per_second": {
+					Type:         schema.TypeFloat,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("JSC_REQUESTS_PER_SECOND", 0),
+					ValidateFunc: validation.FloatAtLeast(0),
+					Description:  "The most requests the provider starts per second, spread evenly, including logins and retries. 0 means no limit. Can also be set with JSC_REQUESTS_PER_SECOND.",
+				},
+				"request_timeout": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("JSC_REQUEST_TIMEOUT", int(auth.DefaultRequestTimeout/time.Second)),
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "Seconds before a single request is abandoned. Each retry gets a fresh timeout. Can also be set with JSC_REQUEST_TIMEOUT.",
+				},
+			},
+			// Define the resources that this provider manages
+			ResourcesMap: map[string]*schema.Resource{
+				"jsc_admin":           admin.ResourceAdmin(),
+				"jsc_oktaidp":         idp.ResourceOktaIdp(),
+				"jsc_entra_idp":       entraidp.ResourceEntraIdp(),
+				"jsc_blockpage":       blockpages.ResourceBlockPage(),
+				"jsc_ztna":            ztna.Resourceztna(),
+				"jsc_ap":              activationprofiles.ResourceActivationProfile(),
+				"jsc_hostnamemapping": hostnamemapping.ResourceHostnameMapping(),
+				"jsc_pag_ztnaapp":     pagztnaapp.ResourcePAGZTNAApp(),
+				"jsc_access_policy":   ztnaapp.ResourceZTNAApp(),
+				"jsc_swiftconnect":    physicalaccess.ResourceSwiftConnect(),
+				"jsc_secure_policy":   securepolicy.ResourceSecurePolicy(),
+			},
+			// Define the datasources
+			DataSourcesMap: map[string]*schema.Resource{
+				"jsc_routes":              routes.DataSourceRoutes(),
+				"jsc_groupedgws":          groupedgws.DataSourceGroupedGWs(),
+				"jsc_pag_vpnroutes":       pagvpnroutes.DataSourcePAGVPNRoutes(),
+				"jsc_pag_apptemplates":    pagapptemplates.DataSourcePAGAppTemplates(),
+				"jsc_pag_ztnaapp":         pagztnaapp.DataSourcePAGZTNAApp(),
+				"jsc_categories":          categories.DataSourceCategories(),
+				"jsc_groups":              groups.DataSourceGroups(),
+				"jsc_hostnamemapping":     hostnamemapping.DataSourceHostnameMapping(),
+				"jsc_hostnamemappings":    hostnamemapping.DataSourceHostnameMappings(),
+				"jsc_idp_connection":      idp.DataSourceIdpConnection(),
+				"jsc_entra_idps":          entraidp.DataSourceEntraIdps(),
+				"jsc_access_policies":     ztnaapp.DataSourceAccessPolicies(),
+				"jsc_app_template":        ztnaapp.DataSourceAppTemplate(),
+				"jsc_activation_profiles": activationprofiles.DataSourceActivationProfiles(),
+				"jsc_current_session":     currentsession.DataSourceCurrentSession(),
+				"jsc_customers":           customers.DataSourceCustomers(),
+			},
+		}
+		p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			// TerraformVersion is only known once Configure has been called
+			client, err := providerConfigure(ctx, d, p.UserAgent("terraform-provider-jsc", version))
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+			return client, nil
+		}
+		auditResources(p.ResourcesMap)
+		return p
+	}
+}
+
+// auditResources wraps the CRUD functions of every resource so the requests they make are
+// attributed to the resource type and ID in audit_log_path.
+func auditResources(resources map[string]*schema.Resource) {
+	for name, r := range resources {
+		wrap := func(f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+			if f == nil {
+				return nil
+			}
+			return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+				ctx, done := auth.WithAuditResource(ctx, name, d.Id())
+				diags := f(ctx, d, m)
+				// Gives requests made by a create the ID of the new object
+				done(d.Id())
+				return diags
+			}
+		}
+		r.CreateContext = wrap(r.CreateContext)
+		r.ReadContext = wrap(r.ReadContext)
+		r.UpdateContext = wrap(r.UpdateContext)
+		r.DeleteContext = wrap(r.DeleteContext)
+	}
+}
+
+func providerConfigure(ctx context.Context, d *schema.ResourceData, userAgent string) (interface{}, error) {
+	if err := validateCredentials(d); err != nil {
+		return nil, err
+	}
+
+	// Each provider block gets its own client so aliased providers never share a session
+	client, err := auth.NewClient(ctx, auth.Config{
+		DomainName:        d.Get("domain_name").(string),
+		Username:          d.Get("username").(string),
+		Password:          d.Get("password").(string),
+		Customerid:        d.Get("customerid").(string),
+		Applicationid:     d.Get("applicationid").(string),
+		Applicationsecret: d.Get("applicationsecret").(string),
+		PAGDomainName:     d.Get("pag_domain_name").(string),
+		TotpSecret:        d.Get("totp_secret").(string),
+		BackupCode:        d.Get("backup_code").(string),
+		Retry: auth.RetryPolicy{
+			MaxRetries: d.Get("max_retries").(int),
+			MinWait:    time.Duration(d.Get("retry_min_wait").(int)) * time.Second,
+			MaxWait:    time.Duration(d.Get("retry_max_wait").(int)) * time.Second,
+		},
+		Transport: auth.TransportConfig{
+			ProxyURL:           d.Get("proxy_url").(string),
+			CACertFile:         d.Get("ca_cert_file").(string),
+			CACertPEM:          d.Get("ca_cert_pem").(string),
+			InsecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+			Timeout:            time.Duration(d.Get("request_timeout").(int)) * time.Second,
+			UserAgent:          userAgent,
+		},
+		SessionCacheDir:       d.Get("session_cache_dir").(string),
+		ReadOnly:              d.Get("read_only").(bool),
+		AuditLogPath:          d.Get("audit_log_path").(string),
+		JamfIDRegistration:    d.Get("jamf_id_registration").(string),
+		JamfIDConnection:      d.Get("jamf_id_connection").(string),
+		RequestsPerSecond:     d.Get("requests_per_second").(float64),
+		MaxConcurrentRequests: d.Get("max_concurrent_requests").(int),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Get("username").(string) != "" && !client.RestoreRadarSession(ctx) { //prep work for other auth methods
+		err := client.AuthenticateRadarAPI(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if d.Get("applicationid").(string) != "" && !client.RestorePAGSession() { //do we have the PAG auth model?
+		err := client.AuthenticatePAG(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+// validateCredentials checks that at least one complete set of credentials is present, either
+// RADAR username/password or PAG applicationid/applicationsecret, and reports every problem at once.
+func validateCredentials(d *schema.ResourceData) error {
+	username := d.Get("username").(string)
+	password := d.Get("password").(string)
+	applicationid := d.Get("applicationid").(string)
+	applicationsecret := d.Get("applicationsecret").(string)
+
+	var problems []string
+	if username == "" && applicationid == "" {
+		problems = append(problems, "no credentials provided: set username and password (JSC_USERNAME, JSC_PASSWORD) for RADAR resources and/or applicationid and applicationsecret (JSC_APPLICATION_ID, JSC_APPLICATION_SECRET) for PAG resources")
+	}
+	if username != "" && password == "" {
+		problems = append(problems, "username is set but password is missing: set password or JSC_PASSWORD")
+	}
+	if username == "" && password != "" {
+		problems = append(problems, "password is set but username is missing: set username or JSC_USERNAME")
+	}
+	if applicationid != "" && applicationsecret == "" {
+		problems = append(problems, "applicationid is set but applicationsecret is missing: set applicationsecret or JSC_APPLICATION_SECRET")
+	}
+	if applicationid == "" && applicationsecret != "" {
+		problems = append(problems, "applicationsecret is set but applicationid is missing: set applicationid or JSC_APPLICATION_ID")
+	}
+	if d.Get("retry_min_wait").(int) > d.Get("retry_max_wait").(int) {
+		problems = append(problems, "retry_min_wait must not be greater than retry_max_wait")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid provider configuration:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2025, Jamf Software LLC.
+package provider_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"jsctfprovider/internal/acctest"
+	"jsctfprovider/internal/fakejsc"
+	"jsctfprovider/internal/provider"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+func TestProvider(t *testing.T) {
+	if err := provider.New("test")().InternalValidate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProviderServer(t *testing.T) {
+	s := fakejsc.New(t)
+	server := acctest.ProviderServer(t, s)
+
+	schemaResp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// jsc_admin comes from the SDK provider, jsc_uemc from the framework one
+	for _, name := range []string{"jsc_admin", "jsc_uemc"} {
+		if _, ok := schemaResp.ResourceSchemas[name]; !ok {
+			t.Errorf("%s is not served", name)
+		}
+	}
+
+	// The framework provider reuses the session of the SDK one
+	logins := slices.DeleteFunc(s.Requests(), func(r string) bool { return r != "POST /auth/v1/credentials" })
+	if len(logins) != 1 {
+		t.Errorf("logged in %d times, want 1", len(logins))
+	}
+}
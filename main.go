@@ -2,31 +2,12 @@
 package main
 
 import (
-	"fmt"
-	"jsctfprovider/endpoints/admin"
-	"jsctfprovider/endpoints/activationprofiles"
-	"jsctfprovider/endpoints/blockpages"
-	entraidp "jsctfprovider/endpoints/entra_idp"
-	"jsctfprovider/endpoints/categories"
-	"jsctfprovider/endpoints/groups"
-	"jsctfprovider/endpoints/groupedgws"
-	"jsctfprovider/endpoints/hostnamemapping"
-	"jsctfprovider/endpoints/idp"
-	pagapptemplates "jsctfprovider/endpoints/pag_apptemplates"
-	pagvpnroutes "jsctfprovider/endpoints/pag_vpnroutes"
-	pagztnaapp "jsctfprovider/endpoints/pag_ztna_app"
-	physicalaccess "jsctfprovider/endpoints/physical_access"
-	"jsctfprovider/endpoints/routes"
-	"jsctfprovider/endpoints/securepolicy"
-	ztnaapp "jsctfprovider/endpoints/ztna_app"
-	"jsctfprovider/endpoints/uemc"
-	"jsctfprovider/endpoints/ztna"
-	"jsctfprovider/internal/auth"
+	"context"
 	"log"
-	"os"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"jsctfprovider/internal/provider"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6/tf6server"
 )
 
 // Run "go generate" to format example terraform files and generate the docs for the registry/website
@@ -39,140 +20,20 @@ import (
 // can be customized.
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs generate --provider-name jsc
 
-var (
-	DomainName        string
-	Username          string
-	Password          string
-	Customerid        string
-	Applicationid     string
-	Applicationsecret string
-)
+// version is set by goreleaser at build time
+var version = "dev"
 
 func main() {
 
 	log.SetFlags(log.Flags() &^ (log.Ldate | log.Ltime))
 
-	// Create a new plugin with a specific provider
-	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: func() *schema.Provider {
-			return &schema.Provider{
-				Schema: map[string]*schema.Schema{
-					"domain_name": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Default:     "radar.wandera.com",
-						Description: "The JSC domain.",
-					},
-					"username": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "The JSC username used for authentication. Must be local account - SSO or SAML not supported.",
-					},
-					"password": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Sensitive:   true,
-						Description: "The JSC password used for authentication.",
-					},
-					"customerid": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Default:     "empty",
-						Description: "The optional customerID. If not provided, the provider will attempt to discover.",
-					},
-					"applicationid": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "The optional applicationid. Required for PAG resource types.",
-					},
-					"applicationsecret": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Sensitive:   true,
-						Description: "The optional applicationsecret. Required for PAG resource types.",
-					},
-				},
-				// Define the resources that this provider manages
-				ResourcesMap: map[string]*schema.Resource{
-					"jsc_admin":           admin.ResourceAdmin(),
-					"jsc_oktaidp":         idp.ResourceOktaIdp(),
-					"jsc_entra_idp":       entraidp.ResourceEntraIdp(),
-					"jsc_uemc":            uemc.ResourceUEMC(),
-					"jsc_blockpage":       blockpages.ResourceBlockPage(),
-					"jsc_ztna":            ztna.Resourceztna(),
-					"jsc_ap":              activationprofiles.ResourceActivationProfile(),
-					"jsc_hostnamemapping": hostnamemapping.ResourceHostnameMapping(),
-					"jsc_pag_ztnaapp":     pagztnaapp.ResourcePAGZTNAApp(),
-					"jsc_access_policy":   ztnaapp.ResourceZTNAApp(),
-					"jsc_swiftconnect":    physicalaccess.ResourceSwiftConnect(),
-					"jsc_secure_policy":   securepolicy.ResourceSecurePolicy(),
-				},
-				// Define the datasources
-				DataSourcesMap: map[string]*schema.Resource{
-					"jsc_routes":              routes.DataSourceRoutes(),
-					"jsc_groupedgws":          groupedgws.DataSourceGroupedGWs(),
-					"jsc_pag_vpnroutes":       pagvpnroutes.DataSourcePAGVPNRoutes(),
-					"jsc_pag_apptemplates":    pagapptemplates.DataSourcePAGAppTemplates(),
-					"jsc_pag_ztnaapp":         pagztnaapp.DataSourcePAGZTNAApp(),
-					"jsc_categories":          categories.DataSourceCategories(),
-					"jsc_groups":              groups.DataSourceGroups(),
-					"jsc_hostnamemapping":     hostnamemapping.DataSourceHostnameMapping(),
-					"jsc_hostnamemappings":    hostnamemapping.DataSourceHostnameMappings(),
-					"jsc_idp_connection":      idp.DataSourceIdpConnection(),
-					"jsc_entra_idps":          entraidp.DataSourceEntraIdps(),
-					"jsc_access_policies":     ztnaapp.DataSourceAccessPolicies(),
-					"jsc_app_template":        ztnaapp.DataSourceAppTemplate(),
-					"jsc_activation_profiles": activationprofiles.DataSourceActivationProfiles(),
-				},
-				ConfigureFunc: providerConfigure,
-			}
-
-		},
-	})
-
-}
-
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	// Read the domain_name field from the configuration and assign it to domainName
-	DomainName = d.Get("domain_name").(string)
-
-	errStore := auth.StoreRadarAuthVars(DomainName)
-	if errStore != nil {
-		return nil, errStore
+	// Serve the SDK and framework providers muxed together over protocol 6
+	server, err := provider.ProviderServer(context.Background(), version)
+	if err != nil {
+		log.Fatal(err)
 	}
-
-	// Assign username and password from configuration
-	Username = d.Get("username").(string)
-	Password = d.Get("password").(string)
-	Customerid = d.Get("customerid").(string)
-	Applicationid = d.Get("applicationid").(string)
-	Applicationsecret = d.Get("applicationsecret").(string)
-
-	if Username != "" { //prep work for other auth methods
-		err := auth.AuthenticateRadarAPI(DomainName, Username, Password, Customerid)
-		if err != nil {
-			return nil, err
-		}
-	}
-	if Applicationid != "" { //do we have the PAG auth model?
-		err := auth.AuthenticatePAG(Applicationid, Applicationsecret)
-		if err != nil {
-			return nil, err
-		}
+	if err := tf6server.Serve("provider", server); err != nil {
+		log.Fatal(err)
 	}
 
-	return nil, nil
-}
-
-// GetClientPassword retrieves the 'password' value from the Terraform configuration.
-// If it's not present in the configuration, it attempts to fetch it from the JSC_PASSWORD environment variable.
-func GetClientPassword(d *schema.ResourceData) (string, error) {
-	password := d.Get("password").(string)
-	if password == "" {
-		password = os.Getenv("JSC_PASSWORD")
-		if password == "" {
-			return "", fmt.Errorf("password must be provided either as an environment variable (JSC_PASSWORD) or in the Terraform configuration")
-		}
-	}
-	return password, nil
 }